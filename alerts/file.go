@@ -0,0 +1,37 @@
+package alerts
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"os"
+	"sync"
+)
+
+// File - tambahkan alert ke file lokal, satu JSON per baris
+type File struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+func (f *File) Notify(alert models.StockAlert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
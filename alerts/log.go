@@ -0,0 +1,13 @@
+package alerts
+
+import (
+	"kasir-api/models"
+	"log"
+)
+
+type Log struct{}
+
+func (Log) Notify(alert models.StockAlert) error {
+	log.Printf("low stock: %s (id %d) stock %d, min %d, reorder %d", alert.ProductName, alert.ProductID, alert.Stock, alert.MinStock, alert.ReorderQty)
+	return nil
+}
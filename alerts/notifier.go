@@ -0,0 +1,61 @@
+// Package alerts mengirim alert stok menipis ke log, webhook atau file lokal.
+package alerts
+
+import (
+	"errors"
+	"kasir-api/models"
+	"strings"
+)
+
+type Notifier interface {
+	Notify(alert models.StockAlert) error
+}
+
+// Multi - kirim ke beberapa notifier sekaligus, error pertama yang dikembalikan
+type Multi []Notifier
+
+func (m Multi) Notify(alert models.StockAlert) error {
+	var first error
+	for _, n := range m {
+		if err := n.Notify(alert); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// New - pilih notifier dari config: "log" (default), "webhook", "file",
+// atau beberapa sekaligus dipisah koma, misal "log,webhook"
+func New(kinds string, webhookURL string, filePath string) (Notifier, error) {
+	multi := Multi{}
+	for _, kind := range strings.Split(kinds, ",") {
+		n, err := newNotifier(strings.TrimSpace(kind), webhookURL, filePath)
+		if err != nil {
+			return nil, err
+		}
+		multi = append(multi, n)
+	}
+
+	if len(multi) == 1 {
+		return multi[0], nil
+	}
+	return multi, nil
+}
+
+func newNotifier(kind string, webhookURL string, filePath string) (Notifier, error) {
+	switch kind {
+	case "", "log":
+		return Log{}, nil
+	case "webhook":
+		if webhookURL == "" {
+			return nil, errors.New("ALERT_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return NewWebhook(webhookURL), nil
+	case "file":
+		if filePath == "" {
+			return nil, errors.New("ALERT_FILE is required for the file notifier")
+		}
+		return NewFile(filePath), nil
+	}
+	return nil, errors.New("unknown alert notifier " + kind + ", use log, webhook or file")
+}
@@ -0,0 +1,53 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	TypeEAN13   = "ean13"
+	TypeCode128 = "code128"
+)
+
+// Barcode - simbol 1D dalam bentuk deretan modul, true = bar hitam, false = spasi
+type Barcode struct {
+	Type    string
+	Content string
+	Modules []bool
+}
+
+// Encode - encode content sesuai symbology yang diminta
+func Encode(kind string, content string) (*Barcode, error) {
+	switch kind {
+	case TypeEAN13:
+		return EAN13(content)
+	case TypeCode128:
+		return Code128(content)
+	default:
+		return nil, fmt.Errorf("unsupported barcode type %q", kind)
+	}
+}
+
+// Auto - pakai EAN-13 kalau content valid EAN-13, selain itu Code128
+func Auto(content string) (*Barcode, error) {
+	if content == "" {
+		return nil, errors.New("barcode content is empty")
+	}
+	if ValidEAN13(content) {
+		return EAN13(content)
+	}
+	return Code128(content)
+}
+
+// appendPattern - tambah modul dari pola lebar (bar, spasi, bar, ...)
+func appendPattern(modules []bool, widths string) []bool {
+	dark := true
+	for _, w := range widths {
+		for i := 0; i < int(w-'0'); i++ {
+			modules = append(modules, dark)
+		}
+		dark = !dark
+	}
+	return modules
+}
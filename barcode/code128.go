@@ -0,0 +1,72 @@
+package barcode
+
+import "errors"
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code128 - encode ASCII printable pakai code set B, atau code set C kalau isinya angka genap
+func Code128(content string) (*Barcode, error) {
+	if content == "" {
+		return nil, errors.New("barcode content is empty")
+	}
+
+	var values []int
+	if isEvenDigits(content) {
+		values = append(values, code128StartC)
+		for i := 0; i < len(content); i += 2 {
+			values = append(values, int(content[i]-'0')*10+int(content[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for i := 0; i < len(content); i++ {
+			c := content[i]
+			if c < 32 || c > 126 {
+				return nil, errors.New("Code128 supports printable ASCII only")
+			}
+			values = append(values, int(c)-32)
+		}
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += values[i] * i
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	modules := make([]bool, 0, len(values)*11+2)
+	for _, v := range values {
+		modules = appendPattern(modules, code128Patterns[v])
+	}
+
+	return &Barcode{Type: TypeCode128, Content: content, Modules: modules}, nil
+}
+
+func isEvenDigits(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
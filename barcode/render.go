@@ -0,0 +1,65 @@
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// QuietZone - jumlah modul kosong di kiri dan kanan simbol
+const QuietZone = 10
+
+// SVG - render barcode ke SVG, moduleWidth dan height dalam pixel
+func SVG(b *Barcode, moduleWidth, height int) []byte {
+	width := (len(b.Modules) + 2*QuietZone) * moduleWidth
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+	WriteSVGBars(&buf, b, QuietZone*moduleWidth, 0, moduleWidth, height)
+	buf.WriteString("</svg>")
+	return buf.Bytes()
+}
+
+// WriteSVGBars - tulis elemen <rect> untuk setiap bar, dipakai juga oleh label
+func WriteSVGBars(w io.Writer, b *Barcode, x, y, moduleWidth, height int) {
+	for i := 0; i < len(b.Modules); {
+		if !b.Modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(b.Modules) && b.Modules[i] {
+			i++
+		}
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="#000"/>`, x+start*moduleWidth, y, (i-start)*moduleWidth, height)
+	}
+}
+
+// DrawBars - gambar bar ke image di posisi (x, y)
+func DrawBars(img *image.Gray, b *Barcode, x, y, moduleWidth, height int) {
+	for i, dark := range b.Modules {
+		if !dark {
+			continue
+		}
+		for dx := 0; dx < moduleWidth; dx++ {
+			for dy := 0; dy < height; dy++ {
+				img.SetGray(x+i*moduleWidth+dx, y+dy, color.Gray{Y: 0})
+			}
+		}
+	}
+}
+
+// PNG - render barcode ke PNG, moduleWidth dan height dalam pixel
+func PNG(w io.Writer, b *Barcode, moduleWidth, height int) error {
+	width := (len(b.Modules) + 2*QuietZone) * moduleWidth
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	DrawBars(img, b, QuietZone*moduleWidth, 0, moduleWidth, height)
+	return png.Encode(w, img)
+}
@@ -0,0 +1,4 @@
+-- Barcode produk (EAN-13 pabrik atau kode internal prefix 21)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_barcode_key ON products (barcode) WHERE barcode IS NOT NULL;
@@ -0,0 +1,23 @@
+-- Dimensi opsi produk (misal Ukuran: S, M, L) dan varian hasil kombinasinya
+CREATE TABLE IF NOT EXISTS product_options (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    "values" TEXT[] NOT NULL DEFAULT '{}',
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    options JSONB NOT NULL DEFAULT '{}',
+    price INT,
+    stock INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS product_variants_product_id_idx ON product_variants (product_id);
+
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id);
@@ -0,0 +1,18 @@
+-- Produk bundle / paket: stok diambil dari produk komponennya
+CREATE TABLE IF NOT EXISTS product_components (
+    bundle_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+
+-- Komponen yang benar-benar terpakai saat bundle terjual
+CREATE TABLE IF NOT EXISTS transaction_detail_components (
+    id SERIAL PRIMARY KEY,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS transaction_detail_components_detail_idx ON transaction_detail_components (transaction_detail_id);
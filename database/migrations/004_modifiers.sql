@@ -0,0 +1,32 @@
+-- Modifier / add-on (extra shot, less sugar, large size)
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT false,
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta INT NOT NULL DEFAULT 0
+);
+
+-- Grup modifier bisa dipasang ke produk atau ke kategori
+CREATE TABLE IF NOT EXISTS modifier_group_links (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    product_id INT REFERENCES products(id) ON DELETE CASCADE,
+    category_id INT REFERENCES categories(id) ON DELETE CASCADE,
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS transaction_detail_modifiers (
+    id SERIAL PRIMARY KEY,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    modifier_id INT REFERENCES modifiers(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    price_delta INT NOT NULL
+);
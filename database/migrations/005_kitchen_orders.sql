@@ -0,0 +1,8 @@
+-- Routing kategori ke station dapur (kitchen, bar), NULL = tidak dikirim ke dapur
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS station VARCHAR(20);
+
+-- Status pesanan hanya diisi untuk transaksi yang punya item dapur
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS order_status VARCHAR(20);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status_updated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS transactions_order_status_idx ON transactions (order_status) WHERE order_status IN ('new', 'preparing', 'ready');
@@ -0,0 +1,31 @@
+-- Meja dine-in dan tab (bon terbuka) per meja
+CREATE TABLE IF NOT EXISTS dining_tables (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    area VARCHAR(50) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS tabs (
+    id SERIAL PRIMARY KEY,
+    table_id INT REFERENCES dining_tables(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    merged_into INT REFERENCES tabs(id),
+    opened_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP
+);
+
+-- satu meja hanya boleh punya satu tab terbuka
+CREATE UNIQUE INDEX IF NOT EXISTS tabs_open_table_key ON tabs (table_id) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS tab_items (
+    id SERIAL PRIMARY KEY,
+    tab_id INT NOT NULL REFERENCES tabs(id),
+    product_id INT NOT NULL REFERENCES products(id),
+    variant_id INT REFERENCES product_variants(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    modifiers INT[] NOT NULL DEFAULT '{}',
+    transaction_id INT REFERENCES transactions(id),
+    added_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS tab_items_tab_id_idx ON tab_items (tab_id);
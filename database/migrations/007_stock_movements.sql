@@ -0,0 +1,28 @@
+-- Ledger mutasi stok, append-only. Sengaja tanpa foreign key supaya histori
+-- tetap ada walaupun produk/varian dihapus
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    variant_id INT,
+    quantity_delta INT NOT NULL,
+    balance_after INT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    reference_type VARCHAR(30),
+    reference_id INT,
+    user_name VARCHAR(100),
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_idx ON stock_movements (product_id, created_at);
+
+CREATE OR REPLACE FUNCTION stock_movements_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS stock_movements_immutable ON stock_movements;
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_immutable();
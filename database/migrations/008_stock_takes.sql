@@ -0,0 +1,22 @@
+-- Sesi stock opname: expected_qty adalah snapshot stok saat sesi dimulai
+CREATE TABLE IF NOT EXISTS stock_takes (
+    id SERIAL PRIMARY KEY,
+    category_id INT REFERENCES categories(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    approved_by VARCHAR(100) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    approved_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stock_take_lines (
+    id SERIAL PRIMARY KEY,
+    stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    variant_id INT REFERENCES product_variants(id),
+    expected_qty INT NOT NULL,
+    counted_qty INT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS stock_take_lines_item_key ON stock_take_lines (stock_take_id, product_id, COALESCE(variant_id, 0));
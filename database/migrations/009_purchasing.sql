@@ -0,0 +1,52 @@
+-- Supplier, purchase order dan penerimaan barang (GRN)
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    contact_name VARCHAR(100) NOT NULL DEFAULT '',
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    lead_time_days INT NOT NULL DEFAULT 0 CHECK (lead_time_days >= 0)
+);
+
+-- harga modal terakhir dari penerimaan barang
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    note TEXT NOT NULL DEFAULT '',
+    expected_at DATE,
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ordered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS purchase_orders_supplier_status_idx ON purchase_orders (supplier_id, status);
+
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    variant_id INT REFERENCES product_variants(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    received_qty INT NOT NULL DEFAULT 0 CHECK (received_qty >= 0 AND received_qty <= quantity),
+    unit_cost INT NOT NULL DEFAULT 0 CHECK (unit_cost >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id),
+    note TEXT NOT NULL DEFAULT '',
+    received_by VARCHAR(100) NOT NULL DEFAULT '',
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id SERIAL PRIMARY KEY,
+    goods_receipt_id INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id INT NOT NULL REFERENCES purchase_order_items(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_cost INT NOT NULL CHECK (unit_cost >= 0)
+);
@@ -0,0 +1,3 @@
+-- Snapshot harga modal per baris transaksi untuk hitung HPP (COGS) dan laba kotor.
+-- products.cost_price sudah ada sejak 009, sekarang dihitung rata-rata tertimbang saat penerimaan barang
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_cost INT NOT NULL DEFAULT 0;
@@ -0,0 +1,28 @@
+-- Lot stok dengan tanggal kedaluwarsa. Stok produk tetap di products/product_variants,
+-- selisih stok dengan total sisa lot dianggap stok tanpa lot (paling tua, habis duluan)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS lot_policy VARCHAR(10) NOT NULL DEFAULT 'fifo';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS allow_expired_sale BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS stock_lots (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id),
+    variant_id INT REFERENCES product_variants(id) ON DELETE CASCADE,
+    goods_receipt_id INT REFERENCES goods_receipts(id),
+    lot_code VARCHAR(64) NOT NULL DEFAULT '',
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expiry_date DATE,
+    unit_cost INT NOT NULL DEFAULT 0,
+    quantity_received INT NOT NULL CHECK (quantity_received > 0),
+    quantity_remaining INT NOT NULL CHECK (quantity_remaining >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS stock_lots_product_idx ON stock_lots (product_id, variant_id) WHERE quantity_remaining > 0;
+CREATE INDEX IF NOT EXISTS stock_lots_expiry_idx ON stock_lots (expiry_date) WHERE quantity_remaining > 0;
+
+-- lot mana yang terpakai oleh mutasi stok keluar
+CREATE TABLE IF NOT EXISTS stock_lot_consumptions (
+    id SERIAL PRIMARY KEY,
+    lot_id INT NOT NULL REFERENCES stock_lots(id) ON DELETE CASCADE,
+    stock_movement_id BIGINT NOT NULL REFERENCES stock_movements(id),
+    quantity INT NOT NULL CHECK (quantity > 0)
+);
@@ -0,0 +1,16 @@
+-- Titik pesan ulang per produk, min_stock 0 = tidak dipantau
+ALTER TABLE products ADD COLUMN IF NOT EXISTS min_stock INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_qty INT NOT NULL DEFAULT 0;
+
+-- Riwayat alert stok menipis yang dipicu checkout
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL,
+    min_stock INT NOT NULL,
+    reorder_qty INT NOT NULL,
+    transaction_id INT REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stock_alerts_created_at_idx ON stock_alerts (created_at);
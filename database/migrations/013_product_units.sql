@@ -0,0 +1,27 @@
+-- Satuan jual/beli per produk. Stok selalu disimpan dalam satuan dasar (base_unit),
+-- factor = isi satuan dalam satuan dasar, misal slop = 10 bungkus
+ALTER TABLE products ADD COLUMN IF NOT EXISTS base_unit VARCHAR(20) NOT NULL DEFAULT 'pcs';
+
+CREATE TABLE IF NOT EXISTS product_units (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    factor INT NOT NULL CHECK (factor > 0),
+    price INT,
+    barcode VARCHAR(64) UNIQUE,
+    UNIQUE (product_id, name)
+);
+
+-- quantity tetap dalam satuan yang dijual, base_quantity yang mengurangi stok
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_id INT REFERENCES product_units(id) ON DELETE SET NULL;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_name VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS base_quantity INT;
+UPDATE transaction_details SET base_quantity = quantity WHERE base_quantity IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN base_quantity SET NOT NULL;
+
+ALTER TABLE tab_items ADD COLUMN IF NOT EXISTS unit_id INT REFERENCES product_units(id);
+
+-- factor disimpan saat PO dibuat supaya penerimaan tidak berubah kalau satuan diedit
+ALTER TABLE purchase_order_items ADD COLUMN IF NOT EXISTS unit_id INT REFERENCES product_units(id);
+ALTER TABLE purchase_order_items ADD COLUMN IF NOT EXISTS unit_name VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE purchase_order_items ADD COLUMN IF NOT EXISTS unit_factor INT NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Waktu update dan arsip untuk listing produk/kategori, archived_at NULL = aktif
+ALTER TABLE products ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS products_name_idx ON products (name);
+CREATE INDEX IF NOT EXISTS products_price_idx ON products (price);
+CREATE INDEX IF NOT EXISTS products_updated_at_idx ON products (updated_at);
+CREATE INDEX IF NOT EXISTS products_category_idx ON products (category_id);
@@ -0,0 +1,4 @@
+-- Gambar produk, isinya key storage (bukan URL) supaya lokasi storage bisa dipindah
+ALTER TABLE products ADD COLUMN IF NOT EXISTS image_key VARCHAR(255);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS image_medium_key VARCHAR(255);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS image_thumb_key VARCHAR(255);
@@ -0,0 +1,7 @@
+-- Kategori bertingkat (Minuman > Kopi > Kopi Sachet), parent_id NULL = kategori utama.
+-- Siklus dicegah di aplikasi saat memindahkan kategori
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES categories(id);
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_parent_not_self;
+ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS categories_parent_idx ON categories (parent_id);
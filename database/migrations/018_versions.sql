@@ -0,0 +1,4 @@
+-- Optimistic concurrency: version naik setiap perubahan katalog, dikirim ke client sebagai ETag.
+-- Perubahan stok dari transaksi tidak menaikkan version
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- Setiap ronde pesanan tab dengan item dapur/bar jadi satu tiket dapur, dibuat saat item ditambahkan.
+-- Saat settle, transaksi tidak membuat tiket baru, item tab tetap merujuk ke rondenya
+CREATE TABLE IF NOT EXISTS tab_rounds (
+    id SERIAL PRIMARY KEY,
+    tab_id INT NOT NULL REFERENCES tabs(id),
+    order_status VARCHAR(20) NOT NULL DEFAULT 'new',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    status_updated_at TIMESTAMP
+);
+
+ALTER TABLE tab_items ADD COLUMN IF NOT EXISTS round_id INT REFERENCES tab_rounds(id);
+
+CREATE INDEX IF NOT EXISTS tab_rounds_order_status_idx ON tab_rounds (order_status) WHERE order_status IN ('new', 'preparing', 'ready');
+CREATE INDEX IF NOT EXISTS tab_items_round_id_idx ON tab_items (round_id);
@@ -0,0 +1,9 @@
+-- Pembayaran per bagian untuk transaksi yang dibagi rata (split bill), jumlahnya sama dengan total_amount
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    share INT NOT NULL CHECK (share > 0),
+    amount INT NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (transaction_id, share)
+);
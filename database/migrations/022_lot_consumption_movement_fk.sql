@@ -0,0 +1,10 @@
+-- Database yang sudah menjalankan 011 sebelum perbaikan: samakan tipe dengan stock_movements.id (BIGSERIAL) dan tambah foreign key
+ALTER TABLE stock_lot_consumptions ALTER COLUMN stock_movement_id TYPE BIGINT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'stock_lot_consumptions_stock_movement_id_fkey') THEN
+        ALTER TABLE stock_lot_consumptions
+            ADD CONSTRAINT stock_lot_consumptions_stock_movement_id_fkey FOREIGN KEY (stock_movement_id) REFERENCES stock_movements(id);
+    END IF;
+END $$;
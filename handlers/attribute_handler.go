@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type AttributeHandler struct {
+	service *services.AttributeService
+}
+
+func NewAttributeHandler(service *services.AttributeService) *AttributeHandler {
+	return &AttributeHandler{service: service}
+}
+
+// HandleAttributes - GET/POST /v2/product-attributes
+func (h *AttributeHandler) HandleAttributes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AttributeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	attributes, err := h.service.GetAll()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product attributes list", attributes)
+}
+
+func (h *AttributeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var attribute models.AttributeDefinition
+	if err := json.NewDecoder(r.Body).Decode(&attribute); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(&attribute); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New product attribute is added successfully", attribute)
+}
+
+// HandleAttributeByID - GET/PUT/DELETE /v2/product-attributes/{id}
+func (h *AttributeHandler) HandleAttributeByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AttributeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	attribute, err := h.service.GetByID(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Attribute not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product attribute details", attribute)
+}
+
+func (h *AttributeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var attribute models.AttributeDefinition
+	if err := json.NewDecoder(r.Body).Decode(&attribute); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	attribute.ID = id
+	if err := h.service.Update(&attribute); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product attribute ID = "+strconv.Itoa(id)+" is updated successfully", attribute)
+}
+
+func (h *AttributeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product attribute ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
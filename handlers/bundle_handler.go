@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type BundleHandler struct {
+	service *services.BundleService
+}
+
+func NewBundleHandler(service *services.BundleService) *BundleHandler {
+	return &BundleHandler{service: service}
+}
+
+// HandleComponents - GET/PUT /v2/products/{id}/components
+func (h *BundleHandler) HandleComponents(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetComponents(w, r)
+	case http.MethodPut:
+		h.SetComponents(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *BundleHandler) GetComponents(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	components, err := h.service.GetComponents(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Bundle components", components)
+}
+
+func (h *BundleHandler) SetComponents(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var components []models.BundleComponent
+	if err := json.NewDecoder(r.Body).Decode(&components); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	result, err := h.service.SetComponents(id, components)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Bundle components are updated successfully", result)
+}
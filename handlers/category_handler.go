@@ -23,7 +23,7 @@ func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
 	return &CategoryHandler{service: service}
 }
 
-// HandleCategorys - GET /api/produk
+// HandleCategorys - GET /v2/categories?name=&status=&sort=&order=&page=&page_size= (atau per_page=)
 func (h *CategoryHandler) HandleCategorys(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -36,23 +36,19 @@ func (h *CategoryHandler) HandleCategorys(w http.ResponseWriter, r *http.Request
 }
 
 func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	Categorys, err := h.service.GetAll()
+	filter, err := categoryFilter(r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusInternalServerError,
-			Message: "General error",
-			Data:    nil,
-		})
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
-	json.NewEncoder(w).Encode(Response{
-		Status:  http.StatusOK,
-		Message: "Categories list",
-		Data:    Categorys,
-	})
+	Categorys, err := h.service.GetAll(filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Categories list", Categorys)
 }
 
 func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -74,7 +70,7 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(Response{
 			Status:  http.StatusBadRequest,
-			Message: "Invalid request body",
+			Message: err.Error(),
 			Data:    nil,
 		})
 		return
@@ -88,13 +84,15 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HandleCategoryByID - GET/PUT/DELETE /api/produk/{id}
+// HandleCategoryByID - GET/PUT/PATCH/DELETE /v2/categories/{id}
 func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		h.GetByID(w, r)
 	case http.MethodPut:
 		h.Update(w, r)
+	case http.MethodPatch:
+		h.Patch(w, r)
 	case http.MethodDelete:
 		h.Delete(w, r)
 	default:
@@ -128,6 +126,7 @@ func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(Category.Version))
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
 		Message: "Category details",
@@ -149,6 +148,11 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var Category models.Category
 	err = json.NewDecoder(r.Body).Decode(&Category)
 	if err != nil {
@@ -162,17 +166,20 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	Category.ID = id
+	Category.Version = version
 	err = h.service.Update(&Category)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		status := errorStatus(err, http.StatusBadRequest)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusBadRequest,
+			Status:  status,
 			Message: err.Error(),
 			Data:    nil,
 		})
 		return
 	}
 
+	w.Header().Set("ETag", etag(Category.Version))
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
 		Message: "Category ID = " + idStr + " is updated successfully",
@@ -180,7 +187,34 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete - DELETE /api/produk/{id}
+// Patch - PATCH /v2/categories/{id} dengan If-Match, JSON Merge Patch hanya untuk field yang dikirim
+func (h *CategoryHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v2/categories/"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+	data, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.Patch(id, data, version)
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("ETag", etag(category.Version))
+	writeJSON(w, http.StatusOK, "Category is updated successfully", category)
+}
+
+// Delete - DELETE /v2/categories/{id}?reassign_to= dengan If-Match, soft delete (arsip), produknya dipindah ke reassign_to
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	idStr := strings.TrimPrefix(r.URL.Path, "/v2/categories/")
@@ -195,7 +229,7 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Delete(id)
+	reassignTo, err := queryInt(r, "reassign_to")
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(Response{
@@ -206,9 +240,204 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Archive(id, version, reassignTo)
+	if err != nil {
+		status := errorStatus(err, http.StatusBadRequest)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(Response{
+			Status:  status,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
-		Message: "Category ID = " + idStr + " is deleted successfully",
-		Data:    nil,
+		Message: "Category ID = " + idStr + " is archived successfully",
+		Data:    result,
 	})
 }
+
+// HandleTree - GET /v2/categories/tree?status=
+func (h *CategoryHandler) HandleTree(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Tree(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.Tree(r.URL.Query().Get("status"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Categories tree", tree)
+}
+
+// HandleMove - POST /v2/categories/{id}/move
+func (h *CategoryHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Move(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandler) Move(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.MoveCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	// If-Match opsional untuk move
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	category, err := h.service.Move(id, req.ParentID, version)
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("ETag", etag(category.Version))
+
+	writeJSON(w, http.StatusOK, "Category is moved successfully", category)
+}
+
+// HandleArchived - GET /v2/categories/archived?name=&sort=&order=&page=&page_size= (atau per_page=)
+func (h *CategoryHandler) HandleArchived(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetArchived(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandler) GetArchived(w http.ResponseWriter, r *http.Request) {
+	filter, err := categoryFilter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filter.Status = models.ListArchived
+
+	categories, err := h.service.GetAll(filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Archived categories list", categories)
+}
+
+// HandleRestore - POST /v2/categories/{id}/restore
+func (h *CategoryHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Restore(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	// If-Match opsional untuk restore
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	category, err := h.service.Restore(id, version)
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("ETag", etag(category.Version))
+
+	writeJSON(w, http.StatusOK, "Category is restored successfully", category)
+}
+
+// HandlePurge - DELETE /v2/categories/{id}/purge?reassign_to= dengan If-Match, hanya kategori arsip tanpa sub kategori dan histori
+func (h *CategoryHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		h.Purge(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	reassignTo, err := queryInt(r, "reassign_to")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Purge(id, version, reassignTo)
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusConflict), err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Category is purged permanently", result)
+}
+
+func categoryFilter(r *http.Request) (models.CategoryFilter, error) {
+	filter := models.CategoryFilter{
+		Name:   r.URL.Query().Get("name"),
+		Status: r.URL.Query().Get("status"),
+		Sort:   r.URL.Query().Get("sort"),
+	}
+
+	var err error
+	if filter.Page, err = queryInt(r, "page"); err != nil {
+		return filter, err
+	}
+	if filter.PageSize, err = queryPageSize(r); err != nil {
+		return filter, err
+	}
+	filter.Desc, err = querySortDesc(r)
+	return filter, err
+}
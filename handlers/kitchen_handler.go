@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type KitchenHandler struct {
+	service *services.KitchenService
+}
+
+func NewKitchenHandler(service *services.KitchenService) *KitchenHandler {
+	return &KitchenHandler{service: service}
+}
+
+// HandleQueue - GET /api/kitchen/queue?station=kitchen|bar
+func (h *KitchenHandler) HandleQueue(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Queue(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *KitchenHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	tickets, err := h.service.GetQueue(r.URL.Query().Get("station"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Kitchen queue", tickets)
+}
+
+// HandleOrderStatus - PUT /api/transactions/{id}/status
+func (h *KitchenHandler) HandleOrderStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.UpdateStatus(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *KitchenHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.OrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.UpdateStatus(id, req.Status); err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Order status is updated successfully", models.KitchenEvent{TransactionID: id, OrderStatus: req.Status})
+}
+
+// HandleRoundStatus - PUT /api/kitchen/rounds/{id}/status, status tiket ronde tab
+func (h *KitchenHandler) HandleRoundStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.UpdateRoundStatus(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *KitchenHandler) UpdateRoundStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.OrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.UpdateRoundStatus(id, req.Status); err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Order status is updated successfully", models.KitchenEvent{TabRoundID: id, OrderStatus: req.Status})
+}
+
+// HandleStream - GET /api/kitchen/stream?station=kitchen|bar (Server-Sent Events).
+// Event pertama "queue" berisi antrian saat ini, setelahnya "order" per perubahan pesanan
+func (h *KitchenHandler) HandleStream(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Stream(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *KitchenHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	station := r.URL.Query().Get("station")
+	if err := h.service.ValidateStation(station); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, "Streaming not supported", nil)
+		return
+	}
+
+	// subscribe dulu sebelum antrian dibaca supaya perubahan di antaranya tidak terlewat
+	events, cancel := h.service.Subscribe()
+	defer cancel()
+
+	queue, err := h.service.GetQueue(station)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+	data, err := json.Marshal(queue)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: queue\ndata: %s\n\n", data)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event := <-events:
+			// hanya kirim pesanan yang punya item untuk station ini
+			ticket, err := h.service.GetEventTicket(event, station)
+			if err != nil {
+				continue
+			}
+			data, err := json.Marshal(ticket)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: order\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
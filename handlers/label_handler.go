@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"kasir-api/labels"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type LabelHandler struct {
+	service *services.LabelService
+}
+
+func NewLabelHandler(service *services.LabelService) *LabelHandler {
+	return &LabelHandler{service: service}
+}
+
+// HandleProductLabel - GET /v2/products/{id}/label?format=svg|png&type=ean13|code128
+func (h *LabelHandler) HandleProductLabel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ProductLabel(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *LabelHandler) ProductLabel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	label, err := h.service.ProductLabel(id, r.URL.Query().Get("type"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(labels.SVG(*label))
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		labels.PNG(w, *label)
+	default:
+		writeJSON(w, http.StatusBadRequest, "Invalid format, use svg or png", nil)
+	}
+}
+
+// HandleLabelSheet - GET /api/labels?product_ids=1,2,3&category_id=1&skip_missing=true -> PDF A4.
+// Produk yang dilewati karena tanpa barcode dikirim di header X-Skipped-Products
+func (h *LabelHandler) HandleLabelSheet(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.LabelSheet(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *LabelHandler) LabelSheet(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIDList(r.URL.Query().Get("product_ids"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid product_ids", nil)
+		return
+	}
+
+	categoryID := 0
+	if c := r.URL.Query().Get("category_id"); c != "" {
+		categoryID, err = strconv.Atoi(c)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid category_id", nil)
+			return
+		}
+	}
+
+	skipMissing := r.URL.Query().Get("skip_missing") == "true"
+	sheet, skipped, err := h.service.Sheet(ids, categoryID, r.URL.Query().Get("type"), skipMissing)
+	if err != nil {
+		var data interface{}
+		if len(skipped) > 0 {
+			data = map[string][]int{"products_without_barcode": skipped}
+		}
+		writeJSON(w, http.StatusBadRequest, err.Error(), data)
+		return
+	}
+
+	if len(skipped) > 0 {
+		w.Header().Set("X-Skipped-Products", formatIDList(skipped))
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="labels.pdf"`)
+	labels.PDFSheet(w, sheet)
+}
+
+// formatIDList - kebalikan parseIDList
+func formatIDList(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseIDList - "1,2,3" -> []int{1, 2, 3}
+func parseIDList(s string) ([]int, error) {
+	ids := make([]int, 0)
+	if s == "" {
+		return ids, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
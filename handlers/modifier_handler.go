@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type ModifierHandler struct {
+	service *services.ModifierService
+}
+
+func NewModifierHandler(service *services.ModifierService) *ModifierHandler {
+	return &ModifierHandler{service: service}
+}
+
+// HandleGroups - GET/POST /v2/modifier-groups
+func (h *ModifierHandler) HandleGroups(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAllGroups(w, r)
+	case http.MethodPost:
+		h.CreateGroup(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ModifierHandler) GetAllGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.GetAllGroups()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier groups list", groups)
+}
+
+func (h *ModifierHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var group models.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.CreateGroup(&group); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New modifier group is added successfully", group)
+}
+
+// HandleGroupByID - GET/PUT/DELETE /v2/modifier-groups/{id}
+func (h *ModifierHandler) HandleGroupByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetGroupByID(w, r)
+	case http.MethodPut:
+		h.UpdateGroup(w, r)
+	case http.MethodDelete:
+		h.DeleteGroup(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ModifierHandler) GetGroupByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	group, err := h.service.GetGroupByID(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Modifier group not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier group details", group)
+}
+
+func (h *ModifierHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var group models.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	group.ID = id
+	if err := h.service.UpdateGroup(&group); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier group ID = "+strconv.Itoa(id)+" is updated successfully", group)
+}
+
+func (h *ModifierHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteGroup(id); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier group ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
+
+// HandleModifiers - POST /v2/modifier-groups/{id}/modifiers
+func (h *ModifierHandler) HandleModifiers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CreateModifier(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ModifierHandler) CreateModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var modifier models.Modifier
+	if err := json.NewDecoder(r.Body).Decode(&modifier); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	modifier.GroupID = groupID
+	if err := h.service.CreateModifier(&modifier); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New modifier is added successfully", modifier)
+}
+
+// HandleModifierByID - PUT/DELETE /v2/modifier-groups/{id}/modifiers/{modifier_id}
+func (h *ModifierHandler) HandleModifierByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.UpdateModifier(w, r)
+	case http.MethodDelete:
+		h.DeleteModifier(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ModifierHandler) UpdateModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, id, err := modifierPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var modifier models.Modifier
+	if err := json.NewDecoder(r.Body).Decode(&modifier); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	modifier.ID = id
+	modifier.GroupID = groupID
+	if err := h.service.UpdateModifier(&modifier); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier ID = "+strconv.Itoa(id)+" is updated successfully", modifier)
+}
+
+func (h *ModifierHandler) DeleteModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, id, err := modifierPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteModifier(groupID, id); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
+
+// HandleProductModifiers - GET /v2/products/{id}/modifiers
+func (h *ModifierHandler) HandleProductModifiers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetForProduct(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ModifierHandler) GetForProduct(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	groups, err := h.service.GetForProduct(productID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product modifier groups", groups)
+}
+
+func modifierPathIDs(r *http.Request) (int, int, error) {
+	groupID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(r.PathValue("modifier_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return groupID, id, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"kasir-api/barcode"
 	"kasir-api/models"
 	"kasir-api/services"
 	"net/http"
@@ -17,7 +18,7 @@ func NewProductHandler(service *services.ProductService) *ProductHandler {
 	return &ProductHandler{service: service}
 }
 
-// HandleProducts - GET /api/produk
+// HandleProducts - GET /v2/products?name=&category_id=&min_price=&max_price=&in_stock=&tag=&attr.<key>=&status=&sort=&order=&page=&page_size= (atau per_page=)
 func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -30,24 +31,45 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	name := r.URL.Query().Get("name")
-	products, err := h.service.GetAll(name)
+	filter, err := productFilter(r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusInternalServerError,
-			Message: "General error",
-			Data:    nil,
-		})
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
-	json.NewEncoder(w).Encode(Response{
-		Status:  http.StatusOK,
-		Message: "Products list",
-		Data:    products,
-	})
+	products, err := h.service.GetAll(filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Products list", products)
+}
+
+// HandleSearch - GET /v2/products/search?q=&limit=&tag=&attr.<key>=
+func (h *ProductHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Search(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	results, err := h.service.Search(r.URL.Query().Get("q"), limit, queryTags(r), queryAttributes(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Search results", results)
 }
 
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -64,12 +86,12 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Create(&product)
+	err = h.service.Create(&product, requestUser(r))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(Response{
 			Status:  http.StatusBadRequest,
-			Message: "Invalid request body",
+			Message: err.Error(),
 			Data:    nil,
 		})
 		return
@@ -83,13 +105,15 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// HandleProductByID - GET/PUT/DELETE /api/produk/{id}
+// HandleProductByID - GET/PUT/PATCH/DELETE /v2/products/{id}
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		h.GetByID(w, r)
 	case http.MethodPut:
 		h.Update(w, r)
+	case http.MethodPatch:
+		h.Patch(w, r)
 	case http.MethodDelete:
 		h.Delete(w, r)
 	default:
@@ -123,6 +147,7 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(product.Version))
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
 		Message: "Product details",
@@ -144,6 +169,11 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var product models.Product
 	err = json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
@@ -157,17 +187,20 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	product.ID = id
-	err = h.service.Update(&product)
+	product.Version = version
+	err = h.service.Update(&product, requestUser(r))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		status := errorStatus(err, http.StatusBadRequest)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusBadRequest,
+			Status:  status,
 			Message: err.Error(),
 			Data:    nil,
 		})
 		return
 	}
 
+	w.Header().Set("ETag", etag(product.Version))
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
 		Message: "Product ID = " + idStr + " is updated successfully",
@@ -175,7 +208,34 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Delete - DELETE /api/produk/{id}
+// Patch - PATCH /v2/products/{id} dengan If-Match, JSON Merge Patch hanya untuk field yang dikirim
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v2/products/"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+	data, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := h.service.Patch(id, data, version, requestUser(r))
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
+	writeJSON(w, http.StatusOK, "Product is updated successfully", product)
+}
+
+// Delete - DELETE /v2/products/{id} dengan If-Match, soft delete (arsip)
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	idStr := strings.TrimPrefix(r.URL.Path, "/v2/products/")
@@ -190,11 +250,17 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Delete(id)
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.service.Archive(id, version)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		status := errorStatus(err, http.StatusBadRequest)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusBadRequest,
+			Status:  status,
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -203,7 +269,257 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(Response{
 		Status:  http.StatusOK,
-		Message: "Product ID = " + idStr + " is deleted successfully",
+		Message: "Product ID = " + idStr + " is archived successfully",
 		Data:    nil,
 	})
 }
+
+// HandleCategoryProducts - GET /v2/categories/{id}/products?subcategories=true, parameter lain sama dengan listing produk
+func (h *ProductHandler) HandleCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByCategory(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) GetByCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	filter, err := productFilter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filter.DirectCategory = r.URL.Query().Get("subcategories") != "true"
+
+	products, err := h.service.GetByCategory(id, filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Category products list", products)
+}
+
+// HandleArchived - GET /v2/products/archived, parameter sama dengan listing produk
+func (h *ProductHandler) HandleArchived(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetArchived(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) GetArchived(w http.ResponseWriter, r *http.Request) {
+	filter, err := productFilter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	filter.Status = models.ListArchived
+
+	products, err := h.service.GetAll(filter)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Archived products list", products)
+}
+
+// HandleRestore - POST /v2/products/{id}/restore
+func (h *ProductHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Restore(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	// If-Match opsional untuk restore
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	product, err := h.service.Restore(id, version)
+	if err != nil {
+		writeJSON(w, errorStatus(err, http.StatusBadRequest), err.Error(), nil)
+		return
+	}
+
+	w.Header().Set("ETag", etag(product.Version))
+	writeJSON(w, http.StatusOK, "Product is restored successfully", product)
+}
+
+// HandlePurge - DELETE /v2/products/{id}/purge dengan If-Match, hanya produk arsip tanpa histori
+func (h *ProductHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		h.Purge(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	version, ok := requireIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Purge(id, version); err != nil {
+		writeJSON(w, errorStatus(err, http.StatusConflict), err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product is purged permanently", nil)
+}
+
+// HandleProductBarcode - GET/POST /v2/products/{id}/barcode
+func (h *ProductHandler) HandleProductBarcode(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.BarcodeImage(w, r)
+	case http.MethodPost:
+		h.AssignBarcode(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// BarcodeImage - GET /v2/products/{id}/barcode?type=ean13|code128&format=svg|png
+func (h *ProductHandler) BarcodeImage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	code, err := h.service.Barcode(id, r.URL.Query().Get("type"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(barcode.SVG(code, 2, 100))
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		barcode.PNG(w, code, 3, 150)
+	default:
+		writeJSON(w, http.StatusBadRequest, "Invalid format, use svg or png", nil)
+	}
+}
+
+// AssignBarcode - POST /v2/products/{id}/barcode
+func (h *ProductHandler) AssignBarcode(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	product, err := h.service.AssignBarcode(id)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Barcode is generated successfully", product)
+}
+
+// HandleAssignBarcodes - POST /v2/products/barcodes
+func (h *ProductHandler) HandleAssignBarcodes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.AssignMissingBarcodes(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) AssignMissingBarcodes(w http.ResponseWriter, r *http.Request) {
+	products, err := h.service.AssignMissingBarcodes()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Barcodes are generated successfully", products)
+}
+
+func productFilter(r *http.Request) (models.ProductFilter, error) {
+	q := r.URL.Query()
+	filter := models.ProductFilter{
+		Name:       q.Get("name"),
+		InStock:    q.Get("in_stock") == "true",
+		Status:     q.Get("status"),
+		Sort:       q.Get("sort"),
+		Tags:       queryTags(r),
+		Attributes: queryAttributes(r),
+	}
+
+	var err error
+	if filter.CategoryID, err = queryInt(r, "category_id"); err != nil {
+		return filter, err
+	}
+	if filter.MinPrice, err = queryIntPtr(r, "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = queryIntPtr(r, "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.Page, err = queryInt(r, "page"); err != nil {
+		return filter, err
+	}
+	if filter.PageSize, err = queryPageSize(r); err != nil {
+		return filter, err
+	}
+	filter.Desc, err = querySortDesc(r)
+	return filter, err
+}
+
+// queryTags - ?tag=a&tag=b atau ?tags=a,b
+func queryTags(r *http.Request) []string {
+	tags := append([]string(nil), r.URL.Query()["tag"]...)
+	for _, v := range r.URL.Query()["tags"] {
+		tags = append(tags, strings.Split(v, ",")...)
+	}
+	return tags
+}
+
+// queryAttributes - ?attr.<key>=nilai
+func queryAttributes(r *http.Request) map[string]string {
+	attributes := make(map[string]string)
+	for name, values := range r.URL.Query() {
+		if key, ok := strings.CutPrefix(name, "attr."); ok && key != "" && len(values) > 0 {
+			attributes[key] = values[0]
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"io"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ProductImageHandler struct {
+	service *services.ProductImageService
+}
+
+func NewProductImageHandler(service *services.ProductImageService) *ProductImageHandler {
+	return &ProductImageHandler{service: service}
+}
+
+// HandleImage - POST/DELETE /v2/products/{id}/image, POST multipart field "image" atau body mentah
+func (h *ProductImageHandler) HandleImage(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Upload(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	// sisa 1 MB untuk overhead multipart, ukuran gambar sendiri dicek di service
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxImageSize+1<<20)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "image is required", nil)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "image is too large or invalid", nil)
+		return
+	}
+
+	product, err := h.service.Upload(id, data)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product image is uploaded successfully", product)
+}
+
+func (h *ProductImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	product, err := h.service.Delete(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product image is deleted successfully", product)
+}
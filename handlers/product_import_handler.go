@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"kasir-api/services"
+	"kasir-api/spreadsheet"
+	"net/http"
+	"strings"
+)
+
+const maxImportSize = 10 << 20
+
+type ProductImportHandler struct {
+	service *services.ProductImportService
+}
+
+func NewProductImportHandler(service *services.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{service: service}
+}
+
+// HandleImport - POST /v2/products/import?dry_run=true
+// multipart: file (csv/xlsx), mapping (JSON {"name": "Nama Barang"}), atau body mentah dengan ?format=csv|xlsx
+func (h *ProductImportHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Import(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	format := r.URL.Query().Get("format")
+	mapping := map[string]string{}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "file is required", nil)
+			return
+		}
+		defer file.Close()
+		if format == "" {
+			format = spreadsheet.FormatFromName(header.Filename)
+		}
+		if m := r.FormValue("mapping"); m != "" {
+			if err := json.Unmarshal([]byte(m), &mapping); err != nil {
+				writeJSON(w, http.StatusBadRequest, "Invalid mapping", nil)
+				return
+			}
+		}
+		data, err = io.ReadAll(file)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid file", nil)
+			return
+		}
+	} else {
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+			return
+		}
+	}
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, err := h.service.Import(data, format, mapping, dryRun, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	switch {
+	case report.Invalid > 0 && !dryRun:
+		writeJSON(w, http.StatusBadRequest, "Import has invalid rows, nothing is saved", report)
+	case dryRun:
+		writeJSON(w, http.StatusOK, "Import validation report", report)
+	default:
+		writeJSON(w, http.StatusOK, "Products are imported successfully", report)
+	}
+}
+
+// HandleExport - GET /v2/products/export?format=csv|xlsx&status=
+func (h *ProductImportHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Export(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductImportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	// tulis ke buffer dulu supaya error masih bisa dikirim sebagai JSON
+	var buf bytes.Buffer
+	if err := h.service.Export(&buf, format, r.URL.Query().Get("status")); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == spreadsheet.FormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="products.`+format+`"`)
+	w.Write(buf.Bytes())
+}
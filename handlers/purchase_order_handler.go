@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type PurchaseOrderHandler struct {
+	service *services.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service *services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders - GET/POST /api/purchase-orders?status=&supplier_id=
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	supplierID := 0
+	if s := r.URL.Query().Get("supplier_id"); s != "" {
+		var err error
+		supplierID, err = strconv.Atoi(s)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid supplier_id", nil)
+			return
+		}
+	}
+
+	orders, err := h.service.GetAll(r.URL.Query().Get("status"), supplierID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Purchase orders", orders)
+}
+
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	po, err := h.service.Create(req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Purchase order is created successfully", po)
+}
+
+// HandlePurchaseOrderByID - GET/PUT /api/purchase-orders/{id}, PUT hanya untuk draft
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	po, err := h.service.GetByID(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Purchase order not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Purchase order details", po)
+}
+
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	po, err := h.service.Update(id, req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Purchase order ID = "+strconv.Itoa(id)+" is updated successfully", po)
+}
+
+// HandleOrder - POST /api/purchase-orders/{id}/order
+func (h *PurchaseOrderHandler) HandleOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.MarkOrdered(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) MarkOrdered(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	po, err := h.service.MarkOrdered(id)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Purchase order is ordered successfully", po)
+}
+
+// HandleCancel - POST /api/purchase-orders/{id}/cancel
+func (h *PurchaseOrderHandler) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Cancel(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	po, err := h.service.Cancel(id)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Purchase order is cancelled successfully", po)
+}
+
+// HandleReceipts - POST /api/purchase-orders/{id}/receipts (goods received note)
+func (h *PurchaseOrderHandler) HandleReceipts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Receive(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.GoodsReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	po, err := h.service.Receive(id, req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Goods are received successfully", po)
+}
+
+// HandleOutstanding - GET /api/report/purchase-orders/outstanding?supplier_id=
+func (h *PurchaseOrderHandler) HandleOutstanding(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Outstanding(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) Outstanding(w http.ResponseWriter, r *http.Request) {
+	supplierID := 0
+	if s := r.URL.Query().Get("supplier_id"); s != "" {
+		var err error
+		supplierID, err = strconv.Atoi(s)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid supplier_id", nil)
+			return
+		}
+	}
+
+	report, err := h.service.GetOutstanding(supplierID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Outstanding purchase orders", report)
+}
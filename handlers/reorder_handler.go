@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type ReorderHandler struct {
+	service *services.ReorderService
+}
+
+func NewReorderHandler(service *services.ReorderService) *ReorderHandler {
+	return &ReorderHandler{service: service}
+}
+
+// HandleSuggestions - GET /api/inventory/reorder-suggestions?window_days=30&coverage_days=7&supplier_id=&include_all=true
+func (h *ReorderHandler) HandleSuggestions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Suggestions(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReorderHandler) Suggestions(w http.ResponseWriter, r *http.Request) {
+	var params models.ReorderParams
+	for name, dest := range map[string]*int{
+		"window_days":   &params.WindowDays,
+		"coverage_days": &params.CoverageDays,
+		"supplier_id":   &params.SupplierID,
+	} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, "Invalid "+name, nil)
+				return
+			}
+			*dest = n
+		}
+	}
+	params.IncludeAll = r.URL.Query().Get("include_all") == "true"
+
+	suggestions, err := h.service.Suggestions(params)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Reorder suggestions", suggestions)
+}
+
+// HandlePurchaseOrder - POST /api/inventory/reorder-suggestions/purchase-order
+func (h *ReorderHandler) HandlePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CreatePurchaseOrder(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReorderHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req models.ReorderPORequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	po, err := h.service.CreatePurchaseOrder(req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Draft purchase order is created successfully", po)
+}
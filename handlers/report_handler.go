@@ -75,3 +75,108 @@ func (h *ReportHandler) ReportDate(w http.ResponseWriter, r *http.Request) {
 		Data:    report,
 	})
 }
+
+// HandleReportProducts - GET /api/report/products?start_date=&end_date=&rollup=parent|variant|component
+func (h *ReportHandler) HandleReportProducts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ReportProducts(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReportHandler) ReportProducts(w http.ResponseWriter, r *http.Request) {
+	rollup := r.URL.Query().Get("rollup")
+	if rollup != "" && rollup != "parent" && rollup != "variant" && rollup != "component" {
+		writeJSON(w, http.StatusBadRequest, "Invalid rollup, use parent, variant or component", nil)
+		return
+	}
+
+	start_date := r.URL.Query().Get("start_date")
+	end_date := r.URL.Query().Get("end_date")
+	report, err := h.service.GetProductSales(start_date, end_date, rollup)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product Sales Report", report)
+}
+
+// HandleReportCategories - GET /api/report/categories?start_date=&end_date=&parent_id=|level=
+func (h *ReportHandler) HandleReportCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ReportCategories(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReportHandler) ReportCategories(w http.ResponseWriter, r *http.Request) {
+	parentID, err := queryInt(r, "parent_id")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	level, err := queryInt(r, "level")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	start_date := r.URL.Query().Get("start_date")
+	end_date := r.URL.Query().Get("end_date")
+	report, err := h.service.GetCategorySales(start_date, end_date, parentID, level)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Category Sales Report", report)
+}
+
+// HandleReportModifiers - GET /api/report/modifiers?start_date=&end_date=
+func (h *ReportHandler) HandleReportModifiers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ReportModifiers(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReportHandler) ReportModifiers(w http.ResponseWriter, r *http.Request) {
+	start_date := r.URL.Query().Get("start_date")
+	end_date := r.URL.Query().Get("end_date")
+	report, err := h.service.GetModifierSales(start_date, end_date)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Modifier Sales Report", report)
+}
+
+// HandleReportTransactions - GET /api/report/transactions?start_date=&end_date=, laba kotor per transaksi
+func (h *ReportHandler) HandleReportTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.ReportTransactions(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReportHandler) ReportTransactions(w http.ResponseWriter, r *http.Request) {
+	start_date := r.URL.Query().Get("start_date")
+	end_date := r.URL.Query().Get("end_date")
+	report, err := h.service.GetTransactionProfits(start_date, end_date)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Transaction Profit Report", report)
+}
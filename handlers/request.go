@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"kasir-api/models"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// requestUser - nama user yang melakukan perubahan, dikirim client lewat header X-User
+func requestUser(r *http.Request) string {
+	return r.Header.Get("X-User")
+}
+
+// queryInt - query param angka, kosong = 0
+func queryInt(r *http.Request, key string) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("Invalid " + key)
+	}
+	return n, nil
+}
+
+// queryIntPtr - query param angka opsional, kosong = nil
+func queryIntPtr(r *http.Request, key string) (*int, error) {
+	if r.URL.Query().Get(key) == "" {
+		return nil, nil
+	}
+	n, err := queryInt(r, key)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// queryPageSize - page_size, per_page diterima sebagai alias
+func queryPageSize(r *http.Request) (int, error) {
+	if r.URL.Query().Get("page_size") == "" && r.URL.Query().Get("per_page") != "" {
+		return queryInt(r, "per_page")
+	}
+	return queryInt(r, "page_size")
+}
+
+// querySortDesc - order=asc (default) atau desc
+func querySortDesc(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("order") {
+	case "", "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, errors.New("Invalid order, use asc or desc")
+	}
+}
+
+// etag - ETag dari version data, dikirim balik client lewat If-Match
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatchVersion - version dari header If-Match ("3" atau W/"3"), kosong atau * = 0 (tanpa cek version)
+func ifMatchVersion(r *http.Request) (int, error) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, errors.New("Invalid If-Match, use the ETag from GET")
+	}
+	return n, nil
+}
+
+// requireIfMatch - PUT/DELETE wajib mengirim If-Match, false = response error sudah ditulis
+func requireIfMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if r.Header.Get("If-Match") == "" {
+		writeJSON(w, http.StatusPreconditionRequired, "If-Match header is required, use the ETag from GET", nil)
+		return 0, false
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return 0, false
+	}
+	return version, true
+}
+
+// errorStatus - 412 kalau data sudah diubah orang lain sejak GET, 409 kalau status pesanan didahului display lain,
+// 400 untuk ValidationError, selain itu status fallback
+func errorStatus(err error, fallback int) int {
+	var validation *models.ValidationError
+	if errors.As(err, &validation) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, models.ErrVersionConflict) {
+		return http.StatusPreconditionFailed
+	}
+	if errors.Is(err, models.ErrOrderStatusConflict) {
+		return http.StatusConflict
+	}
+	return fallback
+}
+
+// readMergePatch - body PATCH dengan Content-Type application/merge-patch+json (application/json juga diterima)
+func readMergePatch(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json", nil)
+		return nil, false
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return nil, false
+	}
+	return data, true
+}
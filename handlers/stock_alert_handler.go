@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type StockAlertHandler struct {
+	service *services.StockAlertService
+}
+
+func NewStockAlertHandler(service *services.StockAlertService) *StockAlertHandler {
+	return &StockAlertHandler{service: service}
+}
+
+// HandleLowStock - GET /api/inventory/low-stock
+func (h *StockAlertHandler) HandleLowStock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetLowStock(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockAlertHandler) GetLowStock(w http.ResponseWriter, r *http.Request) {
+	items, err := h.service.GetLowStock()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Low stock products", items)
+}
+
+// HandleAlerts - GET /api/inventory/alerts?limit=
+func (h *StockAlertHandler) HandleAlerts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockAlertHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			writeJSON(w, http.StatusBadRequest, "Invalid limit", nil)
+			return
+		}
+	}
+
+	alerts, err := h.service.GetAll(limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock alerts", alerts)
+}
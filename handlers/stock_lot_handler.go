@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type StockLotHandler struct {
+	service *services.StockLotService
+}
+
+func NewStockLotHandler(service *services.StockLotService) *StockLotHandler {
+	return &StockLotHandler{service: service}
+}
+
+// HandleLots - GET/POST /v2/products/{id}/lots?include_empty=true
+func (h *StockLotHandler) HandleLots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByProduct(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockLotHandler) GetByProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	includeEmpty := r.URL.Query().Get("include_empty") == "true"
+	lots, err := h.service.GetByProduct(id, includeEmpty)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock lots", lots)
+}
+
+func (h *StockLotHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.StockLotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	lot, err := h.service.Create(id, req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Stock lot is created successfully", lot)
+}
+
+// HandleExpiring - GET /api/report/expiring-lots?days=30
+func (h *StockLotHandler) HandleExpiring(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Expiring(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockLotHandler) Expiring(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid days", nil)
+			return
+		}
+	}
+
+	lots, err := h.service.GetExpiring(days)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Expiring lots", lots)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type StockMovementHandler struct {
+	service *services.StockMovementService
+}
+
+func NewStockMovementHandler(service *services.StockMovementService) *StockMovementHandler {
+	return &StockMovementHandler{service: service}
+}
+
+// HandleMovements - GET /v2/products/{id}/stock-movements?start_date=&end_date=&limit=
+func (h *StockMovementHandler) HandleMovements(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByProduct(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockMovementHandler) GetByProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			writeJSON(w, http.StatusBadRequest, "Invalid limit", nil)
+			return
+		}
+	}
+
+	start_date := r.URL.Query().Get("start_date")
+	end_date := r.URL.Query().Get("end_date")
+	movements, err := h.service.GetByProduct(id, start_date, end_date, limit)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock movements", movements)
+}
+
+// HandleAdjustments - POST /v2/products/{id}/stock-adjustments
+func (h *StockMovementHandler) HandleAdjustments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Adjust(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockMovementHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	movement, err := h.service.Adjust(id, req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Stock is adjusted successfully", movement)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type StockTakeHandler struct {
+	service *services.StockTakeService
+}
+
+func NewStockTakeHandler(service *services.StockTakeService) *StockTakeHandler {
+	return &StockTakeHandler{service: service}
+}
+
+// HandleStockTakes - GET/POST /api/stock-takes
+func (h *StockTakeHandler) HandleStockTakes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Start(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	stockTakes, err := h.service.GetAll()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock takes", stockTakes)
+}
+
+func (h *StockTakeHandler) Start(w http.ResponseWriter, r *http.Request) {
+	var req models.StartStockTakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	st, err := h.service.Start(req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Stock take is started successfully", st)
+}
+
+// HandleStockTakeByID - GET /api/stock-takes/{id}
+func (h *StockTakeHandler) HandleStockTakeByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	st, err := h.service.GetByID(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Stock take not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock take details", st)
+}
+
+// HandleCounts - POST /api/stock-takes/{id}/counts, satu request = satu batch dari scanner
+func (h *StockTakeHandler) HandleCounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.SubmitCounts(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) SubmitCounts(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var req models.StockTakeCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	st, err := h.service.SubmitCounts(id, req.Counts)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Counts are submitted successfully", st)
+}
+
+// HandleVariances - GET /api/stock-takes/{id}/variances
+func (h *StockTakeHandler) HandleVariances(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Variances(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) Variances(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	st, err := h.service.Variances(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Stock take not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock take variances", st)
+}
+
+// HandleApprove - POST /api/stock-takes/{id}/approve
+func (h *StockTakeHandler) HandleApprove(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Approve(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	// body boleh kosong
+	var req models.StockTakeApproveRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+			return
+		}
+	}
+
+	st, err := h.service.Approve(id, req, requestUser(r))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock take is approved successfully", st)
+}
+
+// HandleCancel - POST /api/stock-takes/{id}/cancel
+func (h *StockTakeHandler) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Cancel(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	st, err := h.service.Cancel(id)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Stock take is cancelled successfully", st)
+}
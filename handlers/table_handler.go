@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type TableHandler struct {
+	service *services.TableService
+}
+
+func NewTableHandler(service *services.TableService) *TableHandler {
+	return &TableHandler{service: service}
+}
+
+// HandleTables - GET/POST /v2/tables
+func (h *TableHandler) HandleTables(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TableHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	tables, err := h.service.GetAll(r.URL.Query().Get("area"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, "General error", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Tables list", tables)
+}
+
+func (h *TableHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var table models.DiningTable
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(&table); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New table is added successfully", table)
+}
+
+// HandleTableByID - GET/PUT/DELETE /v2/tables/{id}
+func (h *TableHandler) HandleTableByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TableHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	table, err := h.service.GetByID(id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Table not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Table details", table)
+}
+
+func (h *TableHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var table models.DiningTable
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	table.ID = id
+	if err := h.service.Update(&table); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Table ID = "+strconv.Itoa(id)+" is updated successfully", table)
+}
+
+func (h *TableHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Table ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
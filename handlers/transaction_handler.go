@@ -42,10 +42,16 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 
 	transaction, err := h.service.Checkout(req.Items, true)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		// item tidak valid (stok kedaluwarsa, varian, modifier, produk arsip) = 400, selain itu kegagalan server
+		status := errorStatus(err, http.StatusInternalServerError)
+		message := "General error"
+		if status != http.StatusInternalServerError {
+			message = err.Error()
+		}
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(Response{
-			Status:  http.StatusInternalServerError,
-			Message: "General error",
+			Status:  status,
+			Message: message,
 			Data:    nil,
 		})
 		return
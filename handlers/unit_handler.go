@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type UnitHandler struct {
+	service *services.UnitService
+}
+
+func NewUnitHandler(service *services.UnitService) *UnitHandler {
+	return &UnitHandler{service: service}
+}
+
+// HandleUnits - GET/POST /v2/products/{id}/units
+func (h *UnitHandler) HandleUnits(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UnitHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	units, err := h.service.GetUnits(productID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Units list", units)
+}
+
+func (h *UnitHandler) Create(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var unit models.ProductUnit
+	if err := json.NewDecoder(r.Body).Decode(&unit); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	unit.ProductID = productID
+	if err := h.service.CreateUnit(&unit); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New unit is added successfully", unit)
+}
+
+// HandleUnitByID - GET/PUT/DELETE /v2/products/{id}/units/{unit_id}
+func (h *UnitHandler) HandleUnitByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UnitHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := unitPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	unit, err := h.service.GetUnit(productID, id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Unit not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Unit details", unit)
+}
+
+func (h *UnitHandler) Update(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := unitPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var unit models.ProductUnit
+	if err := json.NewDecoder(r.Body).Decode(&unit); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	unit.ID = id
+	unit.ProductID = productID
+	if err := h.service.UpdateUnit(&unit); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Unit ID = "+strconv.Itoa(id)+" is updated successfully", unit)
+}
+
+func (h *UnitHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := unitPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteUnit(productID, id); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Unit ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
+
+// HandleLookup - GET /v2/products/lookup?barcode=
+func (h *UnitHandler) HandleLookup(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Lookup(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UnitHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	match, err := h.service.LookupBarcode(r.URL.Query().Get("barcode"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Barcode match", match)
+}
+
+func unitPathIDs(r *http.Request) (int, int, error) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(r.PathValue("unit_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return productID, id, nil
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+)
+
+type VariantHandler struct {
+	service *services.VariantService
+}
+
+func NewVariantHandler(service *services.VariantService) *VariantHandler {
+	return &VariantHandler{service: service}
+}
+
+// HandleOptions - GET/PUT /v2/products/{id}/options
+func (h *VariantHandler) HandleOptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetOptions(w, r)
+	case http.MethodPut:
+		h.SetOptions(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VariantHandler) GetOptions(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	options, err := h.service.GetOptions(productID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product options", options)
+}
+
+func (h *VariantHandler) SetOptions(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var options []models.ProductOption
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.service.SetOptions(productID, options); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Product options are updated successfully", options)
+}
+
+// HandleVariants - GET/POST /v2/products/{id}/variants
+func (h *VariantHandler) HandleVariants(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VariantHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	variants, err := h.service.GetVariants(productID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Variants list", variants)
+}
+
+func (h *VariantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	variant.ProductID = productID
+	if err := h.service.CreateVariant(&variant, requestUser(r)); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "New variant is added successfully", variant)
+}
+
+// HandleGenerateVariants - POST /v2/products/{id}/variants/generate
+func (h *VariantHandler) HandleGenerateVariants(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Generate(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VariantHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	variants, err := h.service.GenerateVariants(productID)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, strconv.Itoa(len(variants))+" variants are generated successfully", variants)
+}
+
+// HandleVariantByID - GET/PUT/DELETE /v2/products/{id}/variants/{variant_id}
+func (h *VariantHandler) HandleVariantByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VariantHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := variantPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	variant, err := h.service.GetVariant(productID, id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, "Variant not found", nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Variant details", variant)
+}
+
+func (h *VariantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := variantPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	variant.ID = id
+	variant.ProductID = productID
+	if err := h.service.UpdateVariant(&variant, requestUser(r)); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Variant ID = "+strconv.Itoa(id)+" is updated successfully", variant)
+}
+
+func (h *VariantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	productID, id, err := variantPathIDs(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.DeleteVariant(productID, id, requestUser(r)); err != nil {
+		writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "Variant ID = "+strconv.Itoa(id)+" is deleted successfully", nil)
+}
+
+func variantPathIDs(r *http.Request) (int, int, error) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(r.PathValue("variant_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return productID, id, nil
+}
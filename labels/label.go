@@ -0,0 +1,43 @@
+package labels
+
+import (
+	"kasir-api/barcode"
+	"strconv"
+	"strings"
+)
+
+// Label - isi satu label rak
+type Label struct {
+	Name    string
+	Price   int
+	Barcode *barcode.Barcode
+}
+
+// FormatRupiah - 12500 -> "Rp 12.500"
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + "Rp " + b.String()
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	if max <= 3 {
+		return string(r[:max])
+	}
+	return string(r[:max-3]) + "..."
+}
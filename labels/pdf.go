@@ -0,0 +1,128 @@
+package labels
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"kasir-api/barcode"
+	"strings"
+)
+
+// ukuran dalam point, kertas A4 dengan 3 x 8 label per halaman
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	sheetColumns = 3
+	sheetRows    = 8
+	labelWidth   = 180.0
+	labelHeight  = 95.0
+	marginX      = (pageWidth - sheetColumns*labelWidth) / 2
+	marginY      = (pageHeight - sheetRows*labelHeight) / 2
+)
+
+// PDFSheet - render label ke lembar A4, otomatis tambah halaman kalau label lebih dari satu lembar
+func PDFSheet(w io.Writer, labels []Label) error {
+	perPage := sheetColumns * sheetRows
+	var pages []string
+	for start := 0; start < len(labels); start += perPage {
+		end := start + perPage
+		if end > len(labels) {
+			end = len(labels)
+		}
+		pages = append(pages, pageContent(labels[start:end]))
+	}
+	if len(pages) == 0 {
+		pages = append(pages, "")
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	writeObj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// object 1 catalog, 2 pages, 3-4 font, lalu pasangan page + content
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+i*2))
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func pageContent(labels []Label) string {
+	var b strings.Builder
+	for i, l := range labels {
+		col := i % sheetColumns
+		row := i / sheetColumns
+		x := marginX + float64(col)*labelWidth
+		// koordinat PDF mulai dari kiri bawah
+		y := pageHeight - marginY - float64(row+1)*labelHeight
+
+		fmt.Fprintf(&b, "0.6 G 0.5 w %.2f %.2f %.2f %.2f re S\n", x+2, y+2, labelWidth-4, labelHeight-4)
+		fmt.Fprintf(&b, "0 g BT /F1 9 Tf %.2f %.2f Td (%s) Tj ET\n", x+8, y+labelHeight-16, pdfString(truncate(l.Name, 34)))
+		fmt.Fprintf(&b, "BT /F2 14 Tf %.2f %.2f Td (%s) Tj ET\n", x+8, y+labelHeight-34, pdfString(FormatRupiah(l.Price)))
+
+		total := len(l.Barcode.Modules) + 2*barcode.QuietZone
+		moduleWidth := (labelWidth - 16) / float64(total)
+		if moduleWidth > 1.2 {
+			moduleWidth = 1.2
+		}
+		barX := x + (labelWidth-float64(total)*moduleWidth)/2 + barcode.QuietZone*moduleWidth
+		for j := 0; j < len(l.Barcode.Modules); {
+			if !l.Barcode.Modules[j] {
+				j++
+				continue
+			}
+			start := j
+			for j < len(l.Barcode.Modules) && l.Barcode.Modules[j] {
+				j++
+			}
+			fmt.Fprintf(&b, "%.3f %.2f %.3f %.2f re f\n", barX+float64(start)*moduleWidth, y+18, float64(j-start)*moduleWidth, 36.0)
+		}
+
+		content := pdfString(l.Barcode.Content)
+		// Helvetica 8pt, lebar angka kira-kira 4.45pt
+		textX := x + labelWidth/2 - float64(len(content))*4.45/2
+		fmt.Fprintf(&b, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n", textX, y+8, content)
+	}
+	return b.String()
+}
+
+// pdfString - escape karakter khusus PDF, karakter di luar Latin-1 diganti '?'
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		case r > 126:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
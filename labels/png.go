@@ -0,0 +1,72 @@
+package labels
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"kasir-api/barcode"
+)
+
+const (
+	pngWidth  = 480
+	pngHeight = 240
+)
+
+// PNG - render satu label rak ke PNG
+func PNG(w io.Writer, l Label) error {
+	img := image.NewGray(image.Rect(0, 0, pngWidth, pngHeight))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	drawBorder(img)
+
+	drawText(img, truncate(l.Name, (pngWidth-24)/(glyphAdvance*2)), 12, 12, 2)
+	drawText(img, FormatRupiah(l.Price), 12, 40, 4)
+
+	total := len(l.Barcode.Modules) + 2*barcode.QuietZone
+	moduleWidth := (pngWidth - 24) / total
+	if moduleWidth < 1 {
+		moduleWidth = 1
+	}
+	x := (pngWidth - total*moduleWidth) / 2
+	barcode.DrawBars(img, l.Barcode, x+barcode.QuietZone*moduleWidth, 90, moduleWidth, 110)
+
+	content := l.Barcode.Content
+	drawText(img, content, (pngWidth-len(content)*glyphAdvance*2)/2, 208, 2)
+
+	return png.Encode(w, img)
+}
+
+func drawBorder(img *image.Gray) {
+	b := img.Bounds()
+	gray := color.Gray{Y: 0x99}
+	for x := 0; x < b.Dx(); x++ {
+		img.SetGray(x, 0, gray)
+		img.SetGray(x, b.Dy()-1, gray)
+	}
+	for y := 0; y < b.Dy(); y++ {
+		img.SetGray(0, y, gray)
+		img.SetGray(b.Dx()-1, y, gray)
+	}
+}
+
+// drawText - tulis teks pakai bitmap font 5x7, scale = ukuran pixel per titik
+func drawText(img *image.Gray, text string, x, y, scale int) {
+	for _, r := range text {
+		rows := glyph(r)
+		for row, bits := range rows {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				for dx := 0; dx < scale; dx++ {
+					for dy := 0; dy < scale; dy++ {
+						img.SetGray(x+col*scale+dx, y+row*scale+dy, color.Gray{Y: 0})
+					}
+				}
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
@@ -0,0 +1,34 @@
+package labels
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"kasir-api/barcode"
+)
+
+const (
+	svgWidth  = 360
+	svgHeight = 180
+)
+
+// SVG - render satu label rak ke SVG
+func SVG(l Label) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(&buf, `<rect x="0.5" y="0.5" width="%d" height="%d" fill="#fff" stroke="#999"/>`, svgWidth-1, svgHeight-1)
+	fmt.Fprintf(&buf, `<text x="10" y="26" font-family="Helvetica, Arial, sans-serif" font-size="16">%s</text>`, html.EscapeString(truncate(l.Name, 36)))
+	fmt.Fprintf(&buf, `<text x="10" y="62" font-family="Helvetica, Arial, sans-serif" font-size="30" font-weight="bold">%s</text>`, FormatRupiah(l.Price))
+
+	total := len(l.Barcode.Modules) + 2*barcode.QuietZone
+	moduleWidth := (svgWidth - 20) / total
+	if moduleWidth < 1 {
+		moduleWidth = 1
+	}
+	x := (svgWidth - total*moduleWidth) / 2
+	barcode.WriteSVGBars(&buf, l.Barcode, x+barcode.QuietZone*moduleWidth, 76, moduleWidth, 76)
+	fmt.Fprintf(&buf, `<text x="%d" y="170" font-family="monospace" font-size="14" text-anchor="middle">%s</text>`, svgWidth/2, html.EscapeString(l.Barcode.Content))
+
+	buf.WriteString("</svg>")
+	return buf.Bytes()
+}
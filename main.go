@@ -3,10 +3,12 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"kasir-api/alerts"
 	"kasir-api/database"
 	"kasir-api/handlers"
 	"kasir-api/repositories"
 	"kasir-api/services"
+	"kasir-api/storage"
 	"log"
 	"net/http"
 	"os"
@@ -17,8 +19,13 @@ import (
 )
 
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
+	Port            string `mapstructure:"PORT"`
+	DBConn          string `mapstructure:"DB_CONN"`
+	AlertNotifier   string `mapstructure:"ALERT_NOTIFIER"`
+	AlertWebhookURL string `mapstructure:"ALERT_WEBHOOK_URL"`
+	AlertFile       string `mapstructure:"ALERT_FILE"`
+	StorageDir      string `mapstructure:"STORAGE_DIR"`
+	StorageBaseURL  string `mapstructure:"STORAGE_BASE_URL"`
 }
 
 type Response struct {
@@ -378,8 +385,19 @@ func main() {
 	// }
 
 	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:            viper.GetString("PORT"),
+		DBConn:          viper.GetString("DB_CONN"),
+		AlertNotifier:   viper.GetString("ALERT_NOTIFIER"),
+		AlertWebhookURL: viper.GetString("ALERT_WEBHOOK_URL"),
+		AlertFile:       viper.GetString("ALERT_FILE"),
+		StorageDir:      viper.GetString("STORAGE_DIR"),
+		StorageBaseURL:  viper.GetString("STORAGE_BASE_URL"),
+	}
+	if config.StorageDir == "" {
+		config.StorageDir = "uploads"
+	}
+	if config.StorageBaseURL == "" {
+		config.StorageBaseURL = "/uploads"
 	}
 
 	db, err := database.InitDB(config.DBConn)
@@ -388,27 +406,210 @@ func main() {
 	}
 	defer db.Close()
 
+	// STORAGE - gambar produk di filesystem lokal, dilayani sendiri kalau base URL berupa path
+	fileStorage, err := storage.NewLocal(config.StorageDir, config.StorageBaseURL)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+	if strings.HasPrefix(fileStorage.BaseURL, "/") {
+		http.Handle(fileStorage.BaseURL+"/", http.StripPrefix(fileStorage.BaseURL+"/", fileStorage.Handler()))
+	}
+
 	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
+	variantRepo := repositories.NewVariantRepository(db)
+	bundleRepo := repositories.NewBundleRepository(db)
+	unitRepo := repositories.NewUnitRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	attributeRepo := repositories.NewAttributeRepository(db)
+	productService := services.NewProductService(productRepo, variantRepo, bundleRepo, unitRepo, categoryRepo, attributeRepo, fileStorage)
 	productHandler := handlers.NewProductHandler(productService)
 
 	http.HandleFunc("/v2/products", productHandler.HandleProducts)
 	http.HandleFunc("/v2/products/", productHandler.HandleProductByID)
+	http.HandleFunc("/v2/products/barcodes", productHandler.HandleAssignBarcodes)
+	http.HandleFunc("/v2/products/search", productHandler.HandleSearch)
+	http.HandleFunc("/v2/products/{id}/barcode", productHandler.HandleProductBarcode)
+	http.HandleFunc("/v2/products/archived", productHandler.HandleArchived)
+	http.HandleFunc("/v2/products/{id}/restore", productHandler.HandleRestore)
+	http.HandleFunc("/v2/products/{id}/purge", productHandler.HandlePurge)
+
+	// ATRIBUT PRODUK - definisi atribut custom, nilainya disimpan di produk
+	attributeService := services.NewAttributeService(attributeRepo)
+	attributeHandler := handlers.NewAttributeHandler(attributeService)
+
+	http.HandleFunc("/v2/product-attributes", attributeHandler.HandleAttributes)
+	http.HandleFunc("/v2/product-attributes/{id}", attributeHandler.HandleAttributeByID)
+
+	// IMPORT/EXPORT
+	productImportService := services.NewProductImportService(productRepo, variantRepo, categoryRepo, attributeRepo)
+	productImportHandler := handlers.NewProductImportHandler(productImportService)
+
+	http.HandleFunc("/v2/products/import", productImportHandler.HandleImport)
+	http.HandleFunc("/v2/products/export", productImportHandler.HandleExport)
+
+	// IMAGE
+	productImageService := services.NewProductImageService(productRepo, fileStorage)
+	productImageHandler := handlers.NewProductImageHandler(productImageService)
+
+	http.HandleFunc("/v2/products/{id}/image", productImageHandler.HandleImage)
+
+	// VARIANT
+	variantService := services.NewVariantService(variantRepo, productRepo)
+	variantHandler := handlers.NewVariantHandler(variantService)
+
+	http.HandleFunc("/v2/products/{id}/options", variantHandler.HandleOptions)
+	http.HandleFunc("/v2/products/{id}/variants", variantHandler.HandleVariants)
+	http.HandleFunc("/v2/products/{id}/variants/generate", variantHandler.HandleGenerateVariants)
+	http.HandleFunc("/v2/products/{id}/variants/{variant_id}", variantHandler.HandleVariantByID)
+
+	// BUNDLE
+	bundleService := services.NewBundleService(bundleRepo, productRepo, variantRepo)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+
+	http.HandleFunc("/v2/products/{id}/components", bundleHandler.HandleComponents)
+
+	// UNIT
+	unitService := services.NewUnitService(unitRepo, productRepo)
+	unitHandler := handlers.NewUnitHandler(unitService)
+
+	http.HandleFunc("/v2/products/{id}/units", unitHandler.HandleUnits)
+	http.HandleFunc("/v2/products/{id}/units/{unit_id}", unitHandler.HandleUnitByID)
+	http.HandleFunc("/v2/products/lookup", unitHandler.HandleLookup)
+
+	// MODIFIER
+	modifierRepo := repositories.NewModifierRepository(db)
+	modifierService := services.NewModifierService(modifierRepo, productRepo)
+	modifierHandler := handlers.NewModifierHandler(modifierService)
+
+	http.HandleFunc("/v2/modifier-groups", modifierHandler.HandleGroups)
+	http.HandleFunc("/v2/modifier-groups/{id}", modifierHandler.HandleGroupByID)
+	http.HandleFunc("/v2/modifier-groups/{id}/modifiers", modifierHandler.HandleModifiers)
+	http.HandleFunc("/v2/modifier-groups/{id}/modifiers/{modifier_id}", modifierHandler.HandleModifierByID)
+	http.HandleFunc("/v2/products/{id}/modifiers", modifierHandler.HandleProductModifiers)
+
+	// STOCK MOVEMENT
+	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	stockMovementService := services.NewStockMovementService(stockMovementRepo, productRepo, variantRepo)
+	stockMovementHandler := handlers.NewStockMovementHandler(stockMovementService)
+
+	http.HandleFunc("/v2/products/{id}/stock-movements", stockMovementHandler.HandleMovements)
+	http.HandleFunc("/v2/products/{id}/stock-adjustments", stockMovementHandler.HandleAdjustments)
+
+	// STOCK LOT
+	stockLotRepo := repositories.NewStockLotRepository(db)
+	stockLotService := services.NewStockLotService(stockLotRepo, productRepo)
+	stockLotHandler := handlers.NewStockLotHandler(stockLotService)
+
+	http.HandleFunc("/v2/products/{id}/lots", stockLotHandler.HandleLots)
+	http.HandleFunc("/api/report/expiring-lots", stockLotHandler.HandleExpiring)
+
+	// LABEL
+	labelService := services.NewLabelService(productRepo)
+	labelHandler := handlers.NewLabelHandler(labelService)
+
+	http.HandleFunc("/v2/products/{id}/label", labelHandler.HandleProductLabel)
+	http.HandleFunc("/api/labels", labelHandler.HandleLabelSheet)
 
-	categoryRepo := repositories.NewCategoryRepository(db)
 	categoryService := services.NewCategoryService(categoryRepo)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
 	http.HandleFunc("/v2/categories", categoryHandler.HandleCategorys)
 	http.HandleFunc("/v2/categories/", categoryHandler.HandleCategoryByID)
+	http.HandleFunc("/v2/categories/archived", categoryHandler.HandleArchived)
+	http.HandleFunc("/v2/categories/tree", categoryHandler.HandleTree)
+	http.HandleFunc("/v2/categories/{id}/move", categoryHandler.HandleMove)
+	http.HandleFunc("/v2/categories/{id}/products", productHandler.HandleCategoryProducts)
+	http.HandleFunc("/v2/categories/{id}/restore", categoryHandler.HandleRestore)
+	http.HandleFunc("/v2/categories/{id}/purge", categoryHandler.HandlePurge)
+
+	// STOCK TAKE
+	stockTakeRepo := repositories.NewStockTakeRepository(db)
+	stockTakeService := services.NewStockTakeService(stockTakeRepo, categoryRepo)
+	stockTakeHandler := handlers.NewStockTakeHandler(stockTakeService)
+
+	http.HandleFunc("/api/stock-takes", stockTakeHandler.HandleStockTakes)
+	http.HandleFunc("/api/stock-takes/{id}", stockTakeHandler.HandleStockTakeByID)
+	http.HandleFunc("/api/stock-takes/{id}/counts", stockTakeHandler.HandleCounts)
+	http.HandleFunc("/api/stock-takes/{id}/variances", stockTakeHandler.HandleVariances)
+	http.HandleFunc("/api/stock-takes/{id}/approve", stockTakeHandler.HandleApprove)
+	http.HandleFunc("/api/stock-takes/{id}/cancel", stockTakeHandler.HandleCancel)
+
+	// PURCHASING
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	http.HandleFunc("/v2/suppliers", supplierHandler.HandleSuppliers)
+	http.HandleFunc("/v2/suppliers/{id}", supplierHandler.HandleSupplierByID)
+
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
+	http.HandleFunc("/api/purchase-orders", purchaseOrderHandler.HandlePurchaseOrders)
+	http.HandleFunc("/api/purchase-orders/{id}", purchaseOrderHandler.HandlePurchaseOrderByID)
+	http.HandleFunc("/api/purchase-orders/{id}/order", purchaseOrderHandler.HandleOrder)
+	http.HandleFunc("/api/purchase-orders/{id}/cancel", purchaseOrderHandler.HandleCancel)
+	http.HandleFunc("/api/purchase-orders/{id}/receipts", purchaseOrderHandler.HandleReceipts)
+	http.HandleFunc("/api/report/purchase-orders/outstanding", purchaseOrderHandler.HandleOutstanding)
+
+	// REORDER
+	reorderRepo := repositories.NewReorderRepository(db)
+	reorderService := services.NewReorderService(reorderRepo, purchaseOrderService)
+	reorderHandler := handlers.NewReorderHandler(reorderService)
+
+	http.HandleFunc("/api/inventory/reorder-suggestions", reorderHandler.HandleSuggestions)
+	http.HandleFunc("/api/inventory/reorder-suggestions/purchase-order", reorderHandler.HandlePurchaseOrder)
+
+	// KITCHEN
+	kitchenRepo := repositories.NewKitchenRepository(db)
+	kitchenService := services.NewKitchenService(kitchenRepo)
+	kitchenHandler := handlers.NewKitchenHandler(kitchenService)
+
+	http.HandleFunc("/api/kitchen/queue", kitchenHandler.HandleQueue)
+	http.HandleFunc("/api/kitchen/stream", kitchenHandler.HandleStream)
+	http.HandleFunc("/api/transactions/{id}/status", kitchenHandler.HandleOrderStatus)
+	http.HandleFunc("/api/kitchen/rounds/{id}/status", kitchenHandler.HandleRoundStatus)
+
+	// STOCK ALERT
+	notifier, err := alerts.New(config.AlertNotifier, config.AlertWebhookURL, config.AlertFile)
+	if err != nil {
+		log.Fatal("Failed to initialize alert notifier:", err)
+	}
+	stockAlertRepo := repositories.NewStockAlertRepository(db)
+	stockAlertService := services.NewStockAlertService(stockAlertRepo, notifier)
+	stockAlertHandler := handlers.NewStockAlertHandler(stockAlertService)
+
+	http.HandleFunc("/api/inventory/low-stock", stockAlertHandler.HandleLowStock)
+	http.HandleFunc("/api/inventory/alerts", stockAlertHandler.HandleAlerts)
 
 	// Transaction
 	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
+	transactionService := services.NewTransactionService(transactionRepo, kitchenService, stockAlertService)
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 
 	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
 
+	// DINE-IN
+	tableRepo := repositories.NewTableRepository(db)
+	tableService := services.NewTableService(tableRepo)
+	tableHandler := handlers.NewTableHandler(tableService)
+
+	http.HandleFunc("/v2/tables", tableHandler.HandleTables)
+	http.HandleFunc("/v2/tables/{id}", tableHandler.HandleTableByID)
+
+	tabRepo := repositories.NewTabRepository(db)
+	tabService := services.NewTabService(tabRepo, tableRepo, kitchenService, stockAlertService)
+	tabHandler := handlers.NewTabHandler(tabService)
+
+	http.HandleFunc("/api/tabs", tabHandler.HandleTabs)
+	http.HandleFunc("/api/tabs/{id}", tabHandler.HandleTabByID)
+	http.HandleFunc("/api/tabs/{id}/items", tabHandler.HandleTabItems)
+	http.HandleFunc("/api/tabs/{id}/items/{item_id}", tabHandler.HandleTabItemByID)
+	http.HandleFunc("/api/tabs/{id}/transfer", tabHandler.HandleTransfer)
+	http.HandleFunc("/api/tabs/{id}/merge", tabHandler.HandleMerge)
+	http.HandleFunc("/api/tabs/{id}/settle", tabHandler.HandleSettle)
+
 	// REPORT
 	reportRepo := repositories.NewReportRepository(db)
 	reportService := services.NewReportService(reportRepo)
@@ -416,6 +617,10 @@ func main() {
 
 	http.HandleFunc("/api/report/hari-ini", reportHandler.HandleReportToday)
 	http.HandleFunc("/api/report", reportHandler.HandleReportDate)
+	http.HandleFunc("/api/report/products", reportHandler.HandleReportProducts)
+	http.HandleFunc("/api/report/modifiers", reportHandler.HandleReportModifiers)
+	http.HandleFunc("/api/report/categories", reportHandler.HandleReportCategories)
+	http.HandleFunc("/api/report/transactions", reportHandler.HandleReportTransactions)
 	//fix
 	addr := "0.0.0.0:" + config.Port
 	fmt.Println("Server running on: ", addr)
@@ -0,0 +1,20 @@
+package models
+
+const (
+	AttributeText    = "text"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+	AttributeDate    = "date"
+	AttributeSelect  = "select"
+)
+
+// AttributeDefinition - atribut custom produk (merk, no. sertifikat halal, lokasi rak, ...).
+// Key dan Type tidak bisa diubah setelah dibuat, Options hanya untuk type select
+type AttributeDefinition struct {
+	ID       int      `json:"id"`
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// BundleComponent - produk komponen dan jumlahnya per satu bundle
+type BundleComponent struct {
+	ComponentID   int    `json:"component_id"`
+	ComponentName string `json:"component_name,omitempty"`
+	Quantity      int    `json:"quantity"`
+}
@@ -1,7 +1,28 @@
 package models
 
+import "time"
+
+// Category - ParentID 0 = kategori utama, Path nama lengkap dari kategori utama ("Minuman > Kopi")
 type Category struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          int        `json:"id"`
+	ParentID    int        `json:"parent_id,omitempty"`
+	Name        string     `json:"name"`
+	Path        string     `json:"path"`
+	Description string     `json:"description"`
+	Station     string     `json:"station"`
+	Version     int        `json:"version"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	Children    []Category `json:"children,omitempty"`
+}
+
+// MoveCategoryRequest - pindahkan kategori beserta seluruh turunannya, ParentID 0 = jadi kategori utama
+type MoveCategoryRequest struct {
+	ParentID int `json:"parent_id"`
+}
+
+// CategoryDeleteResult - produk yang dipindah ke ReassignedTo saat kategori diarsip/dihapus
+type CategoryDeleteResult struct {
+	ReassignedProducts int `json:"reassigned_products"`
+	ReassignedTo       int `json:"reassigned_to,omitempty"`
 }
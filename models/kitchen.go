@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	OrderStatusNew       = "new"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusServed    = "served"
+)
+
+// OrderStatuses - urutan lifecycle pesanan dapur
+var OrderStatuses = []string{OrderStatusNew, OrderStatusPreparing, OrderStatusReady, OrderStatusServed}
+
+// ErrOrderStatusConflict - status pesanan sudah diubah display lain sejak dibaca
+var ErrOrderStatusConflict = errors.New("Order status has been changed by another display, reload and try again")
+
+const (
+	StationKitchen = "kitchen"
+	StationBar     = "bar"
+)
+
+// KitchenTicket - pesanan checkout (TransactionID) atau satu ronde tab (TabRoundID, belum dibayar)
+type KitchenTicket struct {
+	TransactionID int                 `json:"transaction_id,omitempty"`
+	TabRoundID    int                 `json:"tab_round_id,omitempty"`
+	TabID         int                 `json:"tab_id,omitempty"`
+	TableName     string              `json:"table_name,omitempty"`
+	OrderStatus   string              `json:"order_status"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Items         []KitchenTicketItem `json:"items"`
+}
+
+type KitchenTicketItem struct {
+	DetailID    int      `json:"detail_id,omitempty"`
+	TabItemID   int      `json:"tab_item_id,omitempty"`
+	ProductName string   `json:"product_name"`
+	VariantName string   `json:"variant_name,omitempty"`
+	Quantity    int      `json:"quantity"`
+	Station     string   `json:"station"`
+	Modifiers   []string `json:"modifiers,omitempty"`
+}
+
+// KitchenEvent - dikirim ke kitchen display lewat stream
+type KitchenEvent struct {
+	TransactionID int    `json:"transaction_id,omitempty"`
+	TabRoundID    int    `json:"tab_round_id,omitempty"`
+	OrderStatus   string `json:"order_status"`
+}
+
+type OrderStatusRequest struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// status arsip untuk filter listing
+const (
+	ListActive   = "active"
+	ListArchived = "archived"
+	ListAll      = "all"
+)
+
+// Pagination - info halaman, Page mulai dari 1
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// ProductFilter - CategoryID termasuk sub kategori kecuali DirectCategory, MinPrice/MaxPrice nil = tanpa batas,
+// Tags = harus punya semua tag, Attributes = key -> nilai (tidak case-sensitive), NumericAttributes = key atribut
+// bertipe number yang dibandingkan sebagai angka (diisi service). Sort: name, price, stock, updated_at
+type ProductFilter struct {
+	Name              string
+	CategoryID        int
+	DirectCategory    bool
+	MinPrice          *int
+	MaxPrice          *int
+	InStock           bool
+	Tags              []string
+	Attributes        map[string]string
+	NumericAttributes map[string]bool
+	Status            string
+	Sort              string
+	Desc              bool
+	Page              int
+	PageSize          int
+}
+
+type ProductPage struct {
+	Products []Product `json:"products"`
+	Pagination
+}
+
+// CategoryFilter - pagination dan sort sama dengan ProductFilter. Sort: name, updated_at.
+// Filter harga/stok/kategori tidak ada karena kategori tidak punya kolom itu
+type CategoryFilter struct {
+	Name     string
+	Status   string
+	Sort     string
+	Desc     bool
+	Page     int
+	PageSize int
+}
+
+type CategoryPage struct {
+	Categories []Category `json:"categories"`
+	Pagination
+}
@@ -0,0 +1,27 @@
+package models
+
+// ModifierGroup - grup pilihan tambahan, misal "Ukuran" atau "Gula"
+type ModifierGroup struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Required    bool       `json:"required"`
+	MinSelect   int        `json:"min_select"`
+	MaxSelect   int        `json:"max_select"`
+	ProductIDs  []int      `json:"product_ids"`
+	CategoryIDs []int      `json:"category_ids"`
+	Modifiers   []Modifier `json:"modifiers"`
+}
+
+type Modifier struct {
+	ID         int    `json:"id"`
+	GroupID    int    `json:"group_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
+
+// DetailModifier - modifier yang dipilih di satu baris transaksi
+type DetailModifier struct {
+	ModifierID int    `json:"modifier_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
@@ -1,9 +1,36 @@
 package models
 
+import "time"
+
+// Product - Attributes berisi nilai atribut custom per key AttributeDefinition:
+// string, angka, boolean atau tanggal "2006-01-02"
 type Product struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Price        int    `json:"price"`
-	Stock        int    `json:"stock"`
-	CategoryName string `json:"category_name"`
+	ID               int                    `json:"id"`
+	Name             string                 `json:"name"`
+	Price            int                    `json:"price"`
+	CostPrice        int                    `json:"cost_price"`
+	Stock            int                    `json:"stock"`
+	Barcode          string                 `json:"barcode"`
+	SKU              string                 `json:"sku"`
+	CategoryID       int                    `json:"category_id"`
+	CategoryName     string                 `json:"category_name"`
+	Category         *Category              `json:"category,omitempty"`
+	IsBundle         bool                   `json:"is_bundle"`
+	LotPolicy        string                 `json:"lot_policy"`
+	AllowExpiredSale bool                   `json:"allow_expired_sale"`
+	MinStock         int                    `json:"min_stock"`
+	ReorderQty       int                    `json:"reorder_qty"`
+	BaseUnit         string                 `json:"base_unit"`
+	Tags             []string               `json:"tags"`
+	Attributes       map[string]interface{} `json:"attributes"`
+	ImageURL         string                 `json:"image_url,omitempty"`
+	ImageMediumURL   string                 `json:"image_medium_url,omitempty"`
+	ImageThumbURL    string                 `json:"image_thumb_url,omitempty"`
+	Version          int                    `json:"version"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	ArchivedAt       *time.Time             `json:"archived_at,omitempty"`
+	Units            []ProductUnit          `json:"units,omitempty"`
+	Options          []ProductOption        `json:"options,omitempty"`
+	Variants         []ProductVariant       `json:"variants,omitempty"`
+	Components       []BundleComponent      `json:"components,omitempty"`
 }
@@ -0,0 +1,31 @@
+package models
+
+// kolom import/export produk, urutan ini juga urutan kolom export. Tags dipisah koma,
+// setelahnya satu kolom per atribut custom dengan prefix ProductImportAttributePrefix
+var ProductImportColumns = []string{"sku", "name", "category", "price", "cost_price", "stock", "barcode", "base_unit", "min_stock", "reorder_qty", "tags"}
+
+const ProductImportAttributePrefix = "attr:"
+
+// ProductImportRow - hasil validasi satu baris file, Row = nomor baris di file (header = 1).
+// Action create/update, kosong kalau baris ditolak
+type ProductImportRow struct {
+	Row       int      `json:"row"`
+	SKU       string   `json:"sku,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Action    string   `json:"action,omitempty"`
+	ProductID int      `json:"product_id,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Product   *Product `json:"-"`
+}
+
+// ProductImportReport - Created/Updated hanya terisi kalau bukan dry run dan semua baris valid
+type ProductImportReport struct {
+	DryRun    bool               `json:"dry_run"`
+	TotalRows int                `json:"total_rows"`
+	Valid     int                `json:"valid"`
+	Invalid   int                `json:"invalid"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Rows      []ProductImportRow `json:"rows"`
+}
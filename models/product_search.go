@@ -0,0 +1,18 @@
+package models
+
+// ProductSearchResult - hasil pencarian kasir, Score = kemiripan teks (0-1) ditambah bobot popularitas.
+// MatchedSKU diisi kalau yang cocok SKU varian
+type ProductSearchResult struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	SKU           string   `json:"sku"`
+	Barcode       string   `json:"barcode"`
+	Price         int      `json:"price"`
+	Stock         int      `json:"stock"`
+	CategoryName  string   `json:"category_name"`
+	Tags          []string `json:"tags"`
+	MatchedSKU    string   `json:"matched_sku,omitempty"`
+	ImageThumbURL string   `json:"image_thumb_url,omitempty"`
+	QtySold       int      `json:"qty_sold"`
+	Score         float64  `json:"score"`
+}
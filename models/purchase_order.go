@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+const (
+	POStatusDraft             = "draft"
+	POStatusOrdered           = "ordered"
+	POStatusPartiallyReceived = "partially_received"
+	POStatusReceived          = "received"
+	POStatusCancelled         = "cancelled"
+)
+
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	ExpectedAt   *time.Time          `json:"expected_at"`
+	CreatedBy    string              `json:"created_by"`
+	CreatedAt    time.Time           `json:"created_at"`
+	OrderedAt    *time.Time          `json:"ordered_at"`
+	TotalAmount  int                 `json:"total_amount"`
+	Items        []PurchaseOrderItem `json:"items,omitempty"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+type PurchaseOrderItem struct {
+	ID          int    `json:"id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	UnitID      int    `json:"unit_id,omitempty"`
+	UnitName    string `json:"unit_name,omitempty"`
+	UnitFactor  int    `json:"unit_factor"`
+	Quantity    int    `json:"quantity"`
+	ReceivedQty int    `json:"received_qty"`
+	UnitCost    int    `json:"unit_cost"`
+	Subtotal    int    `json:"subtotal"`
+}
+
+// GoodsReceipt - satu kali penerimaan barang (GRN) untuk sebuah PO
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	Note            string             `json:"note"`
+	ReceivedBy      string             `json:"received_by"`
+	ReceivedAt      time.Time          `json:"received_at"`
+	Items           []GoodsReceiptItem `json:"items"`
+}
+
+type GoodsReceiptItem struct {
+	ID                  int `json:"id"`
+	PurchaseOrderItemID int `json:"purchase_order_item_id"`
+	ProductID           int `json:"product_id"`
+	VariantID           int `json:"variant_id,omitempty"`
+	Quantity            int `json:"quantity"`
+	UnitCost            int `json:"unit_cost"`
+}
+
+// PurchaseOrderRequest - unit_cost 0 = pakai harga modal produk saat ini (dikali isi satuan)
+type PurchaseOrderRequest struct {
+	SupplierID int                        `json:"supplier_id"`
+	Note       string                     `json:"note"`
+	ExpectedAt string                     `json:"expected_at"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+}
+
+// PurchaseOrderItemRequest - Quantity dan UnitCost dalam satuan UnitID (0 = satuan dasar)
+type PurchaseOrderItemRequest struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id"`
+	UnitID    int `json:"unit_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost"`
+}
+
+type GoodsReceiptRequest struct {
+	Note  string                    `json:"note"`
+	Items []GoodsReceiptItemRequest `json:"items"`
+}
+
+// GoodsReceiptItemRequest - unit_cost kosong = sesuai harga di PO, setiap baris jadi satu lot stok
+type GoodsReceiptItemRequest struct {
+	PurchaseOrderItemID int    `json:"purchase_order_item_id"`
+	Quantity            int    `json:"quantity"`
+	UnitCost            *int   `json:"unit_cost"`
+	LotCode             string `json:"lot_code"`
+	ExpiryDate          string `json:"expiry_date"`
+}
+
+// OutstandingPO - sisa barang yang belum diterima per supplier
+type OutstandingPO struct {
+	SupplierID       int             `json:"supplier_id"`
+	SupplierName     string          `json:"supplier_name"`
+	Orders           int             `json:"orders"`
+	OutstandingQty   int             `json:"outstanding_qty"`
+	OutstandingValue int             `json:"outstanding_value"`
+	PurchaseOrders   []PurchaseOrder `json:"purchase_orders"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// ReorderSuggestion - per produk, atau per varian untuk produk bervarian.
+// Supplier diambil dari PO terakhir produk tersebut
+type ReorderSuggestion struct {
+	ProductID     int      `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	VariantID     int      `json:"variant_id,omitempty"`
+	VariantName   string   `json:"variant_name,omitempty"`
+	SupplierID    int      `json:"supplier_id,omitempty"`
+	SupplierName  string   `json:"supplier_name,omitempty"`
+	LeadTimeDays  int      `json:"lead_time_days"`
+	Stock         int      `json:"stock"`
+	OnOrder       int      `json:"on_order"`
+	MinStock      int      `json:"min_stock"`
+	ReorderQty    int      `json:"reorder_qty"`
+	QtySold       int      `json:"qty_sold"`
+	AvgDailySales float64  `json:"avg_daily_sales"`
+	DaysOfStock   *float64 `json:"days_of_stock"`
+	ReorderPoint  int      `json:"reorder_point"`
+	SuggestedQty  int      `json:"suggested_qty"`
+	UnitCost      int      `json:"unit_cost"`
+	EstimatedCost int      `json:"estimated_cost"`
+}
+
+// ReorderParams - WindowDays = periode penjualan yang dirata-rata,
+// CoverageDays = berapa hari stok yang ingin dipegang setelah barang datang
+type ReorderParams struct {
+	WindowDays   int  `json:"window_days"`
+	CoverageDays int  `json:"coverage_days"`
+	SupplierID   int  `json:"supplier_id"`
+	IncludeAll   bool `json:"include_all"`
+}
+
+// ReorderPORequest - Items kosong = semua saran untuk supplier tersebut
+type ReorderPORequest struct {
+	ReorderParams
+	Note       string                     `json:"note"`
+	ExpectedAt string                     `json:"expected_at"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+}
@@ -9,6 +9,9 @@ type BestSelling struct {
 
 type Today struct {
 	TotalRevenue      int         `json:"total_revenue"`
+	TotalCost         int         `json:"total_cost"`
+	GrossProfit       int         `json:"gross_profit"`
+	Margin            float64     `json:"margin"`
 	TotalTransactions int         `json:"total_transactions"`
 	BestSellingItem   BestSelling `json:"best_selling_products"`
 }
@@ -17,8 +20,50 @@ type ReportData struct {
 	ID             int       `json:"id"`
 	DateTime       time.Time `json:"datetime"`
 	ProductName    string    `json:"product_name"`
+	VariantName    string    `json:"variant_name,omitempty"`
+	UnitName       string    `json:"unit_name,omitempty"`
 	ProductPrice   int       `json:"product_price"`
 	Qty            int       `json:"qty"`
 	SubTotal       int       `json:"subtotal"`
+	UnitCost       int       `json:"unit_cost"`
+	Cost           int       `json:"cost"`
+	GrossProfit    int       `json:"gross_profit"`
+	Margin         float64   `json:"margin"`
 	RemainingStock int       `json:"remaining_stock"`
 }
+
+// ProductSales - penjualan per produk, atau per varian kalau tidak di-roll up ke induk
+type ProductSales struct {
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	VariantID   int     `json:"variant_id,omitempty"`
+	VariantName string  `json:"variant_name,omitempty"`
+	QtySold     int     `json:"qty_sold"`
+	Revenue     int     `json:"revenue"`
+	Cost        int     `json:"cost"`
+	GrossProfit int     `json:"gross_profit"`
+	Margin      float64 `json:"margin"`
+	// QtyViaBundles - qty yang keluar sebagai komponen bundle (rollup=component)
+	QtyViaBundles int `json:"qty_via_bundles,omitempty"`
+}
+
+// CategorySales - penjualan produk di kategori ini dan semua turunannya, CategoryID 0 = tanpa kategori
+type CategorySales struct {
+	CategoryID   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Path         string  `json:"path"`
+	Products     int     `json:"products"`
+	QtySold      int     `json:"qty_sold"`
+	Revenue      int     `json:"revenue"`
+	Cost         int     `json:"cost"`
+	GrossProfit  int     `json:"gross_profit"`
+	Margin       float64 `json:"margin"`
+}
+
+type ModifierSales struct {
+	ModifierID int    `json:"modifier_id"`
+	Name       string `json:"name"`
+	GroupName  string `json:"group_name"`
+	QtySold    int    `json:"qty_sold"`
+	Revenue    int    `json:"revenue"`
+}
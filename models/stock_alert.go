@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LowStockItem - produk dengan stok <= min_stock
+type LowStockItem struct {
+	ProductID    int    `json:"product_id"`
+	ProductName  string `json:"product_name"`
+	CategoryName string `json:"category_name"`
+	Stock        int    `json:"stock"`
+	MinStock     int    `json:"min_stock"`
+	ReorderQty   int    `json:"reorder_qty"`
+}
+
+type StockAlert struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	Stock         int       `json:"stock"`
+	MinStock      int       `json:"min_stock"`
+	ReorderQty    int       `json:"reorder_qty"`
+	TransactionID int       `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	LotPolicyFIFO = "fifo"
+	LotPolicyFEFO = "fefo"
+)
+
+type StockLot struct {
+	ID                int        `json:"id"`
+	ProductID         int        `json:"product_id"`
+	ProductName       string     `json:"product_name"`
+	VariantID         int        `json:"variant_id,omitempty"`
+	VariantName       string     `json:"variant_name,omitempty"`
+	GoodsReceiptID    int        `json:"goods_receipt_id,omitempty"`
+	LotCode           string     `json:"lot_code"`
+	ReceivedAt        time.Time  `json:"received_at"`
+	ExpiryDate        *time.Time `json:"expiry_date"`
+	DaysLeft          *int       `json:"days_left"`
+	Expired           bool       `json:"expired"`
+	UnitCost          int        `json:"unit_cost"`
+	QuantityReceived  int        `json:"quantity_received"`
+	QuantityRemaining int        `json:"quantity_remaining"`
+	RemainingValue    int        `json:"remaining_value"`
+}
+
+// StockLotRequest - daftarkan stok yang sudah ada (tanpa lot) ke lot baru, stok tidak bertambah
+type StockLotRequest struct {
+	VariantID  int    `json:"variant_id"`
+	LotCode    string `json:"lot_code"`
+	ExpiryDate string `json:"expiry_date"`
+	UnitCost   int    `json:"unit_cost"`
+	Quantity   int    `json:"quantity"`
+}
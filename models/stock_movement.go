@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// alasan mutasi stok
+const (
+	MovementSale       = "sale"
+	MovementRefund     = "refund"
+	MovementAdjustment = "adjustment"
+	MovementReceiving  = "receiving"
+	MovementTransfer   = "transfer"
+	MovementStockTake  = "stock_take"
+)
+
+// StockMovement - satu baris ledger, quantity_delta negatif = stok keluar
+type StockMovement struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	VariantID     int       `json:"variant_id,omitempty"`
+	QuantityDelta int       `json:"quantity_delta"`
+	BalanceAfter  int       `json:"balance_after"`
+	Reason        string    `json:"reason"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   int       `json:"reference_id,omitempty"`
+	User          string    `json:"user,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest - koreksi stok manual
+type StockAdjustmentRequest struct {
+	VariantID     int    `json:"variant_id"`
+	QuantityDelta int    `json:"quantity_delta"`
+	Reason        string `json:"reason"`
+	Note          string `json:"note"`
+}
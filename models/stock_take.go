@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+const (
+	StockTakeOpen      = "open"
+	StockTakeApproved  = "approved"
+	StockTakeCancelled = "cancelled"
+)
+
+type StockTake struct {
+	ID         int               `json:"id"`
+	CategoryID int               `json:"category_id,omitempty"`
+	Status     string            `json:"status"`
+	Note       string            `json:"note"`
+	CreatedBy  string            `json:"created_by"`
+	ApprovedBy string            `json:"approved_by,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	ApprovedAt *time.Time        `json:"approved_at"`
+	Summary    *StockTakeSummary `json:"summary,omitempty"`
+	Lines      []StockTakeLine   `json:"lines,omitempty"`
+}
+
+// StockTakeLine - Variance = counted - expected, nilai dihitung dari UnitValue (harga modal)
+type StockTakeLine struct {
+	ID            int    `json:"id"`
+	ProductID     int    `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	VariantID     int    `json:"variant_id,omitempty"`
+	VariantName   string `json:"variant_name,omitempty"`
+	Expected      int    `json:"expected"`
+	Counted       *int   `json:"counted"`
+	Variance      int    `json:"variance"`
+	UnitValue     int    `json:"unit_value"`
+	VarianceValue int    `json:"variance_value"`
+}
+
+type StockTakeSummary struct {
+	Lines         int `json:"lines"`
+	Counted       int `json:"counted"`
+	Uncounted     int `json:"uncounted"`
+	ShortageQty   int `json:"shortage_qty"`
+	SurplusQty    int `json:"surplus_qty"`
+	ShortageValue int `json:"shortage_value"`
+	SurplusValue  int `json:"surplus_value"`
+	NetValue      int `json:"net_value"`
+}
+
+type StartStockTakeRequest struct {
+	CategoryID int    `json:"category_id"`
+	Note       string `json:"note"`
+}
+
+// StockTakeCount - item bisa dikenali lewat product_id/variant_id, barcode produk/satuan, atau SKU varian.
+// Quantity dalam satuan UnitID atau satuan dari barcode, disimpan dalam satuan dasar.
+// Mode "add" menambah hitungan sebelumnya (scan berulang), "set" (default) menimpa
+type StockTakeCount struct {
+	ProductID int    `json:"product_id"`
+	VariantID int    `json:"variant_id"`
+	UnitID    int    `json:"unit_id"`
+	Barcode   string `json:"barcode"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	Mode      string `json:"mode"`
+}
+
+type StockTakeCountRequest struct {
+	Counts []StockTakeCount `json:"counts"`
+}
+
+// StockTakeApproveRequest - ZeroUncounted = item yang tidak dihitung dianggap 0
+type StockTakeApproveRequest struct {
+	ZeroUncounted bool `json:"zero_uncounted"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+const (
+	TabStatusOpen   = "open"
+	TabStatusClosed = "closed"
+	TabStatusMerged = "merged"
+)
+
+type DiningTable struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Area string `json:"area"`
+}
+
+type Tab struct {
+	ID         int        `json:"id"`
+	TableID    int        `json:"table_id"`
+	TableName  string     `json:"table_name"`
+	Status     string     `json:"status"`
+	MergedInto int        `json:"merged_into,omitempty"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	Total      int        `json:"total"`
+	Unpaid     int        `json:"unpaid"`
+	Items      []TabItem  `json:"items,omitempty"`
+}
+
+// TabItem - Subtotal adalah estimasi harga saat ini, harga final dihitung saat settle.
+// RoundID/KitchenStatus diisi untuk item dapur/bar
+type TabItem struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	VariantID     int       `json:"variant_id,omitempty"`
+	VariantName   string    `json:"variant_name,omitempty"`
+	UnitID        int       `json:"unit_id,omitempty"`
+	UnitName      string    `json:"unit_name,omitempty"`
+	Quantity      int       `json:"quantity"`
+	Modifiers     []int     `json:"modifiers,omitempty"`
+	Subtotal      int       `json:"subtotal"`
+	RoundID       int       `json:"round_id,omitempty"`
+	KitchenStatus string    `json:"kitchen_status,omitempty"`
+	TransactionID int       `json:"transaction_id,omitempty"`
+	AddedAt       time.Time `json:"added_at"`
+}
+
+type OpenTabRequest struct {
+	TableID int `json:"table_id"`
+}
+
+type TabItemsRequest struct {
+	Items []CheckoutItem `json:"items"`
+}
+
+type TabTransferRequest struct {
+	TableID int `json:"table_id"`
+}
+
+type TabMergeRequest struct {
+	SourceTabID int `json:"source_tab_id"`
+}
+
+// TabSettleRequest - ItemIDs kosong = semua item yang belum dibayar, Shares > 1 = bagi rata
+// (satu transaksi, dibayar dalam beberapa bagian yang dicatat sebagai TransactionPayment)
+type TabSettleRequest struct {
+	ItemIDs []int `json:"item_ids"`
+	Shares  int   `json:"shares"`
+}
+
+// TabSettlement - TabRounds = tiket dapur dari item yang dibayar, transaksinya sendiri tidak masuk antrian dapur
+type TabSettlement struct {
+	Transaction *Transaction         `json:"transaction"`
+	TabRounds   []int                `json:"tab_rounds,omitempty"`
+	Payments    []TransactionPayment `json:"payments,omitempty"`
+	TabStatus   string               `json:"tab_status"`
+}
+
+// TransactionPayment - satu bagian pembayaran split bill, Share mulai dari 1
+type TransactionPayment struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Share         int       `json:"share"`
+	Amount        int       `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -5,22 +5,44 @@ import "time"
 type Transaction struct {
 	ID          int                 `json:"id"`
 	TotalAmount int                 `json:"total_amount"`
+	TotalCost   int                 `json:"total_cost"`
+	GrossProfit int                 `json:"gross_profit"`
+	Margin      float64             `json:"margin"`
+	OrderStatus string              `json:"order_status,omitempty"`
 	CreatedAt   time.Time           `json:"created_at"`
 	Details     []TransactionDetail `json:"details"`
 }
 
+// TransactionDetail - Quantity dalam satuan yang dijual, BaseQuantity dalam satuan dasar (yang mengurangi stok).
+// UnitCost adalah snapshot harga modal per satuan jual saat checkout, Margin dalam persen dari Subtotal
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID            int               `json:"id"`
+	TransactionID int               `json:"transaction_id"`
+	ProductID     int               `json:"product_id"`
+	ProductName   string            `json:"product_name,omitempty"`
+	VariantID     int               `json:"variant_id,omitempty"`
+	VariantName   string            `json:"variant_name,omitempty"`
+	Station       string            `json:"station,omitempty"`
+	UnitID        int               `json:"unit_id,omitempty"`
+	UnitName      string            `json:"unit_name,omitempty"`
+	Quantity      int               `json:"quantity"`
+	BaseQuantity  int               `json:"base_quantity"`
+	Subtotal      int               `json:"subtotal"`
+	UnitCost      int               `json:"unit_cost"`
+	Cost          int               `json:"cost"`
+	GrossProfit   int               `json:"gross_profit"`
+	Margin        float64           `json:"margin"`
+	Components    []BundleComponent `json:"components,omitempty"`
+	Modifiers     []DetailModifier  `json:"modifiers,omitempty"`
+	Warnings      []string          `json:"warnings,omitempty"`
 }
 
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int   `json:"product_id"`
+	VariantID int   `json:"variant_id,omitempty"`
+	UnitID    int   `json:"unit_id,omitempty"`
+	Quantity  int   `json:"quantity"`
+	Modifiers []int `json:"modifiers,omitempty"`
 }
 
 type CheckoutRequest struct {
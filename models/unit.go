@@ -0,0 +1,22 @@
+package models
+
+// ProductUnit - Factor = isi satuan ini dalam satuan dasar produk.
+// Price kosong = harga satuan dasar x Factor
+type ProductUnit struct {
+	ID        int    `json:"id"`
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Factor    int    `json:"factor"`
+	Price     *int   `json:"price"`
+	Barcode   string `json:"barcode"`
+}
+
+// BarcodeMatch - hasil scan barcode di POS, UnitID 0 = satuan dasar
+type BarcodeMatch struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	UnitID      int    `json:"unit_id,omitempty"`
+	UnitName    string `json:"unit_name"`
+	Factor      int    `json:"factor"`
+	Price       int    `json:"price"`
+}
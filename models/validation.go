@@ -0,0 +1,17 @@
+package models
+
+import "fmt"
+
+// ValidationError - input client ditolak (400), beda dengan kegagalan database/server (500)
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NewValidationError - seperti fmt.Errorf, hasilnya dikenali handler sebagai kesalahan client
+func NewValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,18 @@
+package models
+
+// ProductOption - satu dimensi opsi, misal Ukuran: S, M, L
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant - kombinasi opsi dengan SKU, harga dan stok sendiri
+type ProductVariant struct {
+	ID        int               `json:"id"`
+	ProductID int               `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Name      string            `json:"name"`
+	Options   map[string]string `json:"options"`
+	Price     *int              `json:"price"`
+	Stock     int               `json:"stock"`
+}
@@ -0,0 +1,6 @@
+package models
+
+import "errors"
+
+// ErrVersionConflict - version yang dikirim client (If-Match) sudah tidak sama dengan di database
+var ErrVersionConflict = errors.New("Data has been changed by someone else, reload and try again")
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type AttributeRepository struct {
+	db *sql.DB
+}
+
+func NewAttributeRepository(db *sql.DB) *AttributeRepository {
+	return &AttributeRepository{db: db}
+}
+
+func (repo *AttributeRepository) GetAll() ([]models.AttributeDefinition, error) {
+	rows, err := repo.db.Query("SELECT id, key, label, type, options, required FROM attribute_definitions ORDER BY label, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	definitions := make([]models.AttributeDefinition, 0)
+	for rows.Next() {
+		var d models.AttributeDefinition
+		err := rows.Scan(&d.ID, &d.Key, &d.Label, &d.Type, pq.Array(&d.Options), &d.Required)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, d)
+	}
+
+	return definitions, rows.Err()
+}
+
+func (repo *AttributeRepository) Create(d *models.AttributeDefinition) error {
+	query := "INSERT INTO attribute_definitions (key, label, type, options, required) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	err := repo.db.QueryRow(query, d.Key, d.Label, d.Type, pq.Array(d.Options), d.Required).Scan(&d.ID)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("attribute key %s already exists", d.Key)
+	}
+	return err
+}
+
+func (repo *AttributeRepository) GetByID(id int) (*models.AttributeDefinition, error) {
+	query := "SELECT id, key, label, type, options, required FROM attribute_definitions WHERE id = $1"
+
+	var d models.AttributeDefinition
+	err := repo.db.QueryRow(query, id).Scan(&d.ID, &d.Key, &d.Label, &d.Type, pq.Array(&d.Options), &d.Required)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Attribute not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Update - key dan type tetap, hanya label, options dan required yang berubah
+func (repo *AttributeRepository) Update(d *models.AttributeDefinition) error {
+	query := "UPDATE attribute_definitions SET label = $1, options = $2, required = $3 WHERE id = $4"
+	result, err := repo.db.Exec(query, d.Label, pq.Array(d.Options), d.Required, d.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Attribute not found")
+	}
+
+	return nil
+}
+
+// Delete - atribut yang masih diisi di produk (termasuk yang diarsip) tidak bisa dihapus
+func (repo *AttributeRepository) Delete(id int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var key string
+	err = tx.QueryRow("SELECT key FROM attribute_definitions WHERE id = $1 FOR UPDATE", id).Scan(&key)
+	if err == sql.ErrNoRows {
+		return errors.New("Attribute not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	var used int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM products WHERE attributes ? $1", key).Scan(&used); err != nil {
+		return err
+	}
+	if used > 0 {
+		return fmt.Errorf("attribute %s is still used by %d products, clear it from them first", key, used)
+	}
+
+	if _, err := tx.Exec("DELETE FROM attribute_definitions WHERE id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetIndex - key -> definisi, untuk validasi nilai atribut produk
+func (repo *AttributeRepository) GetIndex() (map[string]models.AttributeDefinition, error) {
+	definitions, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]models.AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		index[d.Key] = d
+	}
+	return index, nil
+}
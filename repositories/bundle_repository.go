@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type BundleRepository struct {
+	db *sql.DB
+}
+
+func NewBundleRepository(db *sql.DB) *BundleRepository {
+	return &BundleRepository{db: db}
+}
+
+func (repo *BundleRepository) GetComponents(bundleID int) ([]models.BundleComponent, error) {
+	return bundleComponents(repo.db, bundleID)
+}
+
+// SetComponents - ganti semua komponen bundle, list kosong = bukan bundle lagi
+func (repo *BundleRepository) SetComponents(bundleID int, components []models.BundleComponent) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM product_components WHERE bundle_id = $1", bundleID)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range components {
+		_, err = tx.Exec("INSERT INTO product_components (bundle_id, component_id, quantity) VALUES ($1, $2, $3)",
+			bundleID, c.ComponentID, c.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// IsComponent - produk dipakai sebagai komponen bundle lain
+func (repo *BundleRepository) IsComponent(productID int) (bool, error) {
+	var exists bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM product_components WHERE component_id = $1)", productID).Scan(&exists)
+	return exists, err
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// bundleComponents - dipakai juga di dalam transaksi checkout
+func bundleComponents(q queryer, bundleID int) ([]models.BundleComponent, error) {
+	query := `
+		SELECT pc.component_id, p.name, pc.quantity
+		FROM product_components pc
+		JOIN products p ON p.id = pc.component_id
+		WHERE pc.bundle_id = $1
+		ORDER BY pc.component_id
+	`
+	rows, err := q.Query(query, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make([]models.BundleComponent, 0)
+	for rows.Next() {
+		var c models.BundleComponent
+		err := rows.Scan(&c.ComponentID, &c.ComponentName, &c.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+
+	return components, rows.Err()
+}
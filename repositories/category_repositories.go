@@ -3,7 +3,10 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"kasir-api/models"
+	"strconv"
+	"strings"
 )
 
 type CategoryRepository struct {
@@ -14,39 +17,97 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
-	query := "SELECT id, name, description FROM categories"
-	rows, err := repo.db.Query(query)
+// categoryColumns - kolom untuk scanCategory, path dirangkai dari kategori utama sampai kategori ini
+const categoryColumns = `c.id, COALESCE(c.parent_id, 0), c.name, (
+		WITH RECURSIVE up AS (
+			SELECT pc.id, pc.parent_id, pc.name::text AS path FROM categories pc WHERE pc.id = c.id
+			UNION ALL
+			SELECT pc.id, pc.parent_id, pc.name || ' > ' || up.path FROM categories pc JOIN up ON pc.id = up.parent_id
+		)
+		SELECT path FROM up WHERE parent_id IS NULL
+	), c.description, COALESCE(c.station, ''), c.version, c.updated_at, c.archived_at`
+
+// categorySubtree - subquery id kategori beserta semua turunannya, param = placeholder id kategori
+func categorySubtree(param string) string {
+	return `(WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ` + param + `
+			UNION ALL
+			SELECT sc.id FROM categories sc JOIN subtree ON sc.parent_id = subtree.id
+		) SELECT id FROM subtree)`
+}
+
+// categoryAncestors - subquery id kategori beserta semua induknya, column = kolom/placeholder id kategori
+func categoryAncestors(column string) string {
+	return `(WITH RECURSIVE up AS (
+			SELECT id, parent_id FROM categories WHERE id = ` + column + `
+			UNION ALL
+			SELECT ac.id, ac.parent_id FROM categories ac JOIN up ON ac.id = up.parent_id
+		) SELECT id FROM up)`
+}
+
+// categoryStation - station dari kategori itu sendiri atau induk terdekat yang punya station, NULL kalau tidak ada
+func categoryStation(column string) string {
+	return `(WITH RECURSIVE up AS (
+			SELECT id, parent_id, station, 0 AS depth FROM categories WHERE id = ` + column + `
+			UNION ALL
+			SELECT sc.id, sc.parent_id, sc.station, up.depth + 1 FROM categories sc JOIN up ON sc.id = up.parent_id
+		) SELECT station FROM up WHERE station IS NOT NULL ORDER BY depth LIMIT 1)`
+}
+
+var categorySortColumns = map[string]string{
+	"name":       "c.name",
+	"updated_at": "c.updated_at",
+}
+
+// GetAll - listing kategori dengan filter nama/status, sort dan offset pagination
+func (repo *CategoryRepository) GetAll(filter models.CategoryFilter) ([]models.Category, int, error) {
+	where := " WHERE 1 = 1"
+	args := []interface{}{}
+	if filter.Name != "" {
+		args = append(args, "%"+filter.Name+"%")
+		where += " AND c.name ILIKE $" + strconv.Itoa(len(args))
+	}
+	where += archivedFilter("c", filter.Status)
+
+	var total int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM categories c"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + categoryColumns + " FROM categories c" + where +
+		" ORDER BY " + orderBy(categorySortColumns, filter.Sort, filter.Desc, "c.id")
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+
+	rows, err := repo.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	Categoriess := make([]models.Category, 0)
 	for rows.Next() {
-		var p models.Category
-		err := rows.Scan(&p.ID, &p.Name, &p.Description)
+		p, err := scanCategory(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		Categoriess = append(Categoriess, p)
+		Categoriess = append(Categoriess, *p)
 	}
 
-	return Categoriess, nil
+	return Categoriess, total, rows.Err()
 }
 
 func (repo *CategoryRepository) Create(Categories *models.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	err := repo.db.QueryRow(query, Categories.Name, Categories.Description).Scan(&Categories.ID)
+	query := "INSERT INTO categories (parent_id, name, description, station) VALUES (NULLIF($1::int, 0), $2, $3, NULLIF($4, '')) RETURNING id, version"
+	err := repo.db.QueryRow(query, Categories.ParentID, Categories.Name, Categories.Description, Categories.Station).Scan(&Categories.ID, &Categories.Version)
 	return err
 }
 
 // GetByID - ambil produk by ID
 func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+	query := "SELECT " + categoryColumns + " FROM categories c WHERE c.id = $1"
 
-	var p models.Category
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Description)
+	p, err := scanCategory(repo.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, errors.New("Categories not found")
 	}
@@ -54,42 +115,275 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 		return nil, err
 	}
 
-	return &p, nil
+	return p, nil
 }
 
+// Update - parent tidak diubah di sini, pakai Move. Version > 0 = hanya kalau version di database masih sama
 func (repo *CategoryRepository) Update(Categories *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := repo.db.Exec(query, Categories.Name, Categories.Description, Categories.ID)
+	query := `
+		UPDATE categories SET name = $1, description = $2, station = NULLIF($3, ''), updated_at = NOW(), version = version + 1
+		WHERE id = $4 AND ($5::int = 0 OR version = $5)
+		RETURNING version
+	`
+	err := repo.db.QueryRow(query, Categories.Name, Categories.Description, Categories.Station, Categories.ID, Categories.Version).Scan(&Categories.Version)
+	if err == sql.ErrNoRows {
+		return checkVersion(repo.db, "categories", Categories.ID, Categories.Version, "Categories not found")
+	}
+
+	return err
+}
+
+// Move - pindahkan kategori (beserta turunannya) ke parent lain, parent tidak boleh turunan kategori itu sendiri
+func (repo *CategoryRepository) Move(id int, parentID int, version int) error {
+	tx, err := repo.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+	// kunci tabel dari perpindahan lain supaya dua move bersamaan tidak membentuk siklus
+	if _, err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
 		return err
 	}
 
-	if rows == 0 {
+	var current int
+	err = tx.QueryRow("SELECT version FROM categories WHERE id = $1", id).Scan(&current)
+	if err == sql.ErrNoRows {
 		return errors.New("Categories not found")
 	}
+	if err != nil {
+		return err
+	}
+	if version > 0 && version != current {
+		return models.ErrVersionConflict
+	}
+
+	if parentID > 0 {
+		var archived, inSubtree bool
+		err := tx.QueryRow("SELECT archived_at IS NOT NULL, id IN "+categorySubtree("$2")+" FROM categories WHERE id = $1", parentID, id).Scan(&archived, &inSubtree)
+		if err == sql.ErrNoRows {
+			return errors.New("parent category not found")
+		}
+		if err != nil {
+			return err
+		}
+		if archived {
+			return errors.New("parent category is archived")
+		}
+		if inSubtree {
+			return errors.New("category cannot be moved under itself or its own subcategory")
+		}
+	}
 
-	return nil
+	if _, err := tx.Exec("UPDATE categories SET parent_id = NULLIF($1::int, 0), updated_at = NOW(), version = version + 1 WHERE id = $2", parentID, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *CategoryRepository) Delete(id int) error {
-	query := "DELETE FROM categories WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+// GetTree - semua kategori sesuai status tanpa pagination, disusun jadi pohon di service
+func (repo *CategoryRepository) GetTree(status string) ([]models.Category, error) {
+	rows, err := repo.db.Query("SELECT " + categoryColumns + " FROM categories c WHERE 1 = 1" + archivedFilter("c", status) + " ORDER BY c.name, c.id")
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]models.Category, 0)
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, *c)
+	}
+
+	return categories, rows.Err()
+}
+
+// Archive - soft delete. Kategori yang masih berisi produk aktif ditolak kecuali reassignTo diisi,
+// semua produknya (termasuk yang diarsip) dipindah ke reassignTo dalam tx yang sama.
+// Sub kategori yang masih aktif harus diarsip atau dipindah dulu supaya pohon aktif tetap utuh
+func (repo *CategoryRepository) Archive(id int, version int, reassignTo int) (*models.CategoryDeleteResult, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	var current int
+	err = tx.QueryRow("SELECT archived_at IS NOT NULL, version FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&archived, &current)
+	if err == nil && version > 0 && version != current {
+		return nil, models.ErrVersionConflict
+	}
+	if err == sql.ErrNoRows || (err == nil && archived) {
+		return nil, errors.New("Categories not found or already archived")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var activeChildren bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1 AND archived_at IS NULL)", id).Scan(&activeChildren)
+	if err != nil {
+		return nil, err
+	}
+	if activeChildren {
+		return nil, errors.New("category has active subcategories, archive or move them first")
+	}
+
+	result, err := reassignProducts(tx, id, reassignTo, "archived_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("UPDATE categories SET archived_at = NOW(), updated_at = NOW(), version = version + 1 WHERE id = $1", id); err != nil {
+		return nil, err
 	}
-	rows, err := result.RowsAffected()
+
+	return result, tx.Commit()
+}
+
+// Restore - parent yang masih diarsip harus dipulihkan dulu
+func (repo *CategoryRepository) Restore(id int, version int) error {
+	var archivedParent bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM categories c JOIN categories pc ON pc.id = c.parent_id WHERE c.id = $1 AND pc.archived_at IS NOT NULL)", id).Scan(&archivedParent)
 	if err != nil {
 		return err
 	}
+	if archivedParent {
+		return errors.New("parent category is archived, restore it first")
+	}
+	return setArchived(repo.db, "categories", id, version, false, "Categories not found or not archived")
+}
 
-	if rows == 0 {
-		return errors.New("Categories not found")
+// Purge - hapus permanen kategori yang sudah diarsip. Produk (termasuk yang diarsip) harus dipindah
+// lewat reassignTo, sub kategori dan histori stock take tetap menahan kategori di arsip
+func (repo *CategoryRepository) Purge(id int, version int, reassignTo int) (*models.CategoryDeleteResult, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	return err
+	var archived bool
+	var current int
+	err = tx.QueryRow("SELECT archived_at IS NOT NULL, version FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&archived, &current)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Categories not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 && version != current {
+		return nil, models.ErrVersionConflict
+	}
+	if !archived {
+		return nil, errors.New("only archived categories can be purged")
+	}
+
+	result, err := reassignProducts(tx, id, reassignTo, "true")
+	if err != nil {
+		return nil, err
+	}
+
+	var history bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)
+			OR EXISTS (SELECT 1 FROM stock_takes WHERE category_id = $1)
+	`, id).Scan(&history)
+	if err != nil {
+		return nil, err
+	}
+	if history {
+		return nil, errors.New("category still has subcategories or stock take history and can only stay archived")
+	}
+
+	if _, err := tx.Exec("DELETE FROM categories WHERE id = $1", id); err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, errors.New("category is still referenced and can only stay archived")
+		}
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+// reassignProducts - pindahkan semua produk kategori id ke reassignTo. Tanpa reassignTo, kategori yang
+// masih punya produk yang cocok dengan blocking (kondisi SQL) ditolak supaya tidak ada produk yatim
+func reassignProducts(tx *sql.Tx, id int, reassignTo int, blocking string) (*models.CategoryDeleteResult, error) {
+	result := &models.CategoryDeleteResult{ReassignedTo: reassignTo}
+	if reassignTo == 0 {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM products WHERE category_id = $1 AND "+blocking, id).Scan(&count); err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("category still has %d products, use reassign_to to move them to another category", count)
+		}
+		return result, nil
+	}
+
+	if reassignTo == id {
+		return nil, errors.New("reassign_to cannot be the category itself")
+	}
+	var archived bool
+	err := tx.QueryRow("SELECT archived_at IS NOT NULL FROM categories WHERE id = $1 FOR SHARE", reassignTo).Scan(&archived)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("reassign_to category not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if archived {
+		return nil, errors.New("reassign_to category is archived")
+	}
+
+	moved, err := tx.Exec("UPDATE products SET category_id = $1, updated_at = NOW(), version = version + 1 WHERE category_id = $2", reassignTo, id)
+	if err != nil {
+		return nil, err
+	}
+	n, err := moved.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	result.ReassignedProducts = int(n)
+	return result, nil
+}
+
+func scanCategory(row rowScanner) (*models.Category, error) {
+	var c models.Category
+	var archivedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.ParentID, &c.Name, &c.Path, &c.Description, &c.Station, &c.Version, &c.UpdatedAt, &archivedAt); err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		c.ArchivedAt = &archivedAt.Time
+	}
+	return &c, nil
+}
+
+// GetNameIndex - nama dan path kategori aktif (huruf kecil) -> id, untuk import produk.
+// Nama yang dipakai lebih dari satu kategori hanya bisa dicari lewat path-nya
+func (repo *CategoryRepository) GetNameIndex() (map[string]int, error) {
+	categories, err := repo.GetTree(models.ListActive)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	names := make(map[string]int)
+	for _, c := range categories {
+		index[strings.ToLower(c.Path)] = c.ID
+		names[strings.ToLower(c.Name)]++
+	}
+	for _, c := range categories {
+		if name := strings.ToLower(c.Name); names[name] == 1 {
+			index[name] = c.ID
+		}
+	}
+
+	return index, nil
 }
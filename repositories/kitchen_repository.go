@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+type KitchenRepository struct {
+	db *sql.DB
+}
+
+func NewKitchenRepository(db *sql.DB) *KitchenRepository {
+	return &KitchenRepository{db: db}
+}
+
+// GetQueue - pesanan checkout dan ronde tab yang belum served urut waktu masuk, station kosong = semua station
+func (repo *KitchenRepository) GetQueue(station string) ([]models.KitchenTicket, error) {
+	tickets, err := repo.tickets("t.order_status IN ('new', 'preparing', 'ready')", station)
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := repo.roundTickets("r.order_status IN ('new', 'preparing', 'ready')", station)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets = append(tickets, rounds...)
+	sort.SliceStable(tickets, func(i, j int) bool {
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+	return tickets, nil
+}
+
+// GetTicket - satu pesanan, item difilter per station
+func (repo *KitchenRepository) GetTicket(transactionID int, station string) (*models.KitchenTicket, error) {
+	tickets, err := repo.tickets("t.id = $2", station, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, errors.New("Order not found")
+	}
+	return &tickets[0], nil
+}
+
+// GetRoundTicket - satu ronde tab, item difilter per station
+func (repo *KitchenRepository) GetRoundTicket(roundID int, station string) (*models.KitchenTicket, error) {
+	tickets, err := repo.roundTickets("r.id = $2", station, roundID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tickets) == 0 {
+		return nil, errors.New("Tab round not found")
+	}
+	return &tickets[0], nil
+}
+
+func (repo *KitchenRepository) GetStatus(transactionID int) (string, error) {
+	var status sql.NullString
+	err := repo.db.QueryRow("SELECT order_status FROM transactions WHERE id = $1", transactionID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New("Transaction not found")
+	}
+	if err != nil {
+		return "", err
+	}
+	if !status.Valid {
+		return "", errors.New("Transaction has no kitchen order")
+	}
+	return status.String, nil
+}
+
+// UpdateStatus - ubah status hanya kalau status saat ini masih current, supaya dua display tidak sama-sama lolos
+func (repo *KitchenRepository) UpdateStatus(transactionID int, current, status string) error {
+	query := "UPDATE transactions SET order_status = $1, status_updated_at = NOW() WHERE id = $2 AND order_status = $3"
+	result, err := repo.db.Exec(query, status, transactionID, current)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrOrderStatusConflict
+	}
+
+	return nil
+}
+
+func (repo *KitchenRepository) GetRoundStatus(roundID int) (string, error) {
+	var status string
+	err := repo.db.QueryRow("SELECT order_status FROM tab_rounds WHERE id = $1", roundID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New("Tab round not found")
+	}
+	return status, err
+}
+
+// UpdateRoundStatus - sama seperti UpdateStatus untuk ronde tab
+func (repo *KitchenRepository) UpdateRoundStatus(roundID int, current, status string) error {
+	query := "UPDATE tab_rounds SET order_status = $1, status_updated_at = NOW() WHERE id = $2 AND order_status = $3"
+	result, err := repo.db.Exec(query, status, roundID, current)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return models.ErrOrderStatusConflict
+	}
+
+	return nil
+}
+
+// tickets - station item diambil dari kategori produk atau induk terdekat yang punya station
+func (repo *KitchenRepository) tickets(condition string, station string, args ...interface{}) ([]models.KitchenTicket, error) {
+	query := `
+		SELECT t.id, t.order_status, t.created_at, d.id, p.name, COALESCE(v.name, ''), d.quantity, c.station
+		FROM transactions t
+		JOIN transaction_details d ON d.transaction_id = t.id
+		JOIN products p ON p.id = d.product_id
+		CROSS JOIN LATERAL (SELECT ` + categoryStation("p.category_id") + ` AS station) c
+		LEFT JOIN product_variants v ON v.id = d.variant_id
+		WHERE c.station IS NOT NULL
+		  AND ($1 = '' OR c.station = $1)
+		  AND ` + condition + `
+		ORDER BY t.created_at, t.id, d.id
+	`
+	rows, err := repo.db.Query(query, append([]interface{}{station}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tickets := make([]models.KitchenTicket, 0)
+	detailIDs := make([]int, 0)
+	for rows.Next() {
+		var t models.KitchenTicket
+		var item models.KitchenTicketItem
+		err := rows.Scan(&t.TransactionID, &t.OrderStatus, &t.CreatedAt, &item.DetailID, &item.ProductName, &item.VariantName, &item.Quantity, &item.Station)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tickets) == 0 || tickets[len(tickets)-1].TransactionID != t.TransactionID {
+			tickets = append(tickets, t)
+		}
+		last := &tickets[len(tickets)-1]
+		last.Items = append(last.Items, item)
+		detailIDs = append(detailIDs, item.DetailID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	modifiers, err := repo.detailModifierNames(detailIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tickets {
+		for j := range tickets[i].Items {
+			tickets[i].Items[j].Modifiers = modifiers[tickets[i].Items[j].DetailID]
+		}
+	}
+
+	return tickets, nil
+}
+
+// roundTickets - tiket ronde tab, modifier diambil dari nama modifier saat ini karena belum ada snapshot transaksi
+func (repo *KitchenRepository) roundTickets(condition string, station string, args ...interface{}) ([]models.KitchenTicket, error) {
+	query := `
+		SELECT r.id, r.tab_id, COALESCE(dt.name, ''), r.order_status, r.created_at, i.id, p.name, COALESCE(v.name, ''), i.quantity, c.station,
+			ARRAY(SELECT m.name FROM modifiers m WHERE m.id = ANY(i.modifiers) ORDER BY m.id)
+		FROM tab_rounds r
+		JOIN tabs tb ON tb.id = r.tab_id
+		LEFT JOIN dining_tables dt ON dt.id = tb.table_id
+		JOIN tab_items i ON i.round_id = r.id
+		JOIN products p ON p.id = i.product_id
+		CROSS JOIN LATERAL (SELECT ` + categoryStation("p.category_id") + ` AS station) c
+		LEFT JOIN product_variants v ON v.id = i.variant_id
+		WHERE c.station IS NOT NULL
+		  AND ($1 = '' OR c.station = $1)
+		  AND ` + condition + `
+		ORDER BY r.created_at, r.id, i.id
+	`
+	rows, err := repo.db.Query(query, append([]interface{}{station}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tickets := make([]models.KitchenTicket, 0)
+	for rows.Next() {
+		var t models.KitchenTicket
+		var item models.KitchenTicketItem
+		var modifiers pq.StringArray
+		err := rows.Scan(&t.TabRoundID, &t.TabID, &t.TableName, &t.OrderStatus, &t.CreatedAt, &item.TabItemID, &item.ProductName, &item.VariantName, &item.Quantity, &item.Station, &modifiers)
+		if err != nil {
+			return nil, err
+		}
+		item.Modifiers = modifiers
+
+		if len(tickets) == 0 || tickets[len(tickets)-1].TabRoundID != t.TabRoundID {
+			tickets = append(tickets, t)
+		}
+		last := &tickets[len(tickets)-1]
+		last.Items = append(last.Items, item)
+	}
+
+	return tickets, rows.Err()
+}
+
+func (repo *KitchenRepository) detailModifierNames(detailIDs []int) (map[int][]string, error) {
+	result := map[int][]string{}
+	if len(detailIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := repo.db.Query("SELECT transaction_detail_id, name FROM transaction_detail_modifiers WHERE transaction_detail_id = ANY($1) ORDER BY id", pq.Array(detailIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var detailID int
+		var name string
+		if err := rows.Scan(&detailID, &name); err != nil {
+			return nil, err
+		}
+		result[detailID] = append(result[detailID], name)
+	}
+
+	return result, rows.Err()
+}
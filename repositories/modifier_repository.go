@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type ModifierRepository struct {
+	db *sql.DB
+}
+
+func NewModifierRepository(db *sql.DB) *ModifierRepository {
+	return &ModifierRepository{db: db}
+}
+
+const modifierGroupColumns = `
+	g.id, g.name, g.required, g.min_select, g.max_select,
+	COALESCE(ARRAY(SELECT product_id FROM modifier_group_links WHERE group_id = g.id AND product_id IS NOT NULL ORDER BY product_id), '{}'),
+	COALESCE(ARRAY(SELECT category_id FROM modifier_group_links WHERE group_id = g.id AND category_id IS NOT NULL ORDER BY category_id), '{}')
+`
+
+func (repo *ModifierRepository) GetAllGroups() ([]models.ModifierGroup, error) {
+	return modifierGroups(repo.db, "SELECT "+modifierGroupColumns+" FROM modifier_groups g ORDER BY g.id")
+}
+
+func (repo *ModifierRepository) GetGroupByID(id int) (*models.ModifierGroup, error) {
+	groups, err := modifierGroups(repo.db, "SELECT "+modifierGroupColumns+" FROM modifier_groups g WHERE g.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("Modifier group not found")
+	}
+	return &groups[0], nil
+}
+
+// GetGroupsForProduct - grup yang terpasang ke produk langsung atau lewat kategorinya
+func (repo *ModifierRepository) GetGroupsForProduct(productID int) ([]models.ModifierGroup, error) {
+	return productModifierGroups(repo.db, productID)
+}
+
+func (repo *ModifierRepository) CreateGroup(group *models.ModifierGroup) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO modifier_groups (name, required, min_select, max_select) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = tx.QueryRow(query, group.Name, group.Required, group.MinSelect, group.MaxSelect).Scan(&group.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := setModifierGroupLinks(tx, group); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *ModifierRepository) UpdateGroup(group *models.ModifierGroup) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE modifier_groups SET name = $1, required = $2, min_select = $3, max_select = $4 WHERE id = $5"
+	result, err := tx.Exec(query, group.Name, group.Required, group.MinSelect, group.MaxSelect, group.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Modifier group not found")
+	}
+
+	if err := setModifierGroupLinks(tx, group); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *ModifierRepository) DeleteGroup(id int) error {
+	result, err := repo.db.Exec("DELETE FROM modifier_groups WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Modifier group not found")
+	}
+
+	return nil
+}
+
+func (repo *ModifierRepository) CreateModifier(modifier *models.Modifier) error {
+	query := "INSERT INTO modifiers (group_id, name, price_delta) VALUES ($1, $2, $3) RETURNING id"
+	return repo.db.QueryRow(query, modifier.GroupID, modifier.Name, modifier.PriceDelta).Scan(&modifier.ID)
+}
+
+func (repo *ModifierRepository) UpdateModifier(modifier *models.Modifier) error {
+	query := "UPDATE modifiers SET name = $1, price_delta = $2 WHERE id = $3 AND group_id = $4"
+	result, err := repo.db.Exec(query, modifier.Name, modifier.PriceDelta, modifier.ID, modifier.GroupID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Modifier not found")
+	}
+
+	return nil
+}
+
+func (repo *ModifierRepository) DeleteModifier(groupID, id int) error {
+	result, err := repo.db.Exec("DELETE FROM modifiers WHERE id = $1 AND group_id = $2", id, groupID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Modifier not found")
+	}
+
+	return nil
+}
+
+func setModifierGroupLinks(tx *sql.Tx, group *models.ModifierGroup) error {
+	_, err := tx.Exec("DELETE FROM modifier_group_links WHERE group_id = $1", group.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, productID := range group.ProductIDs {
+		_, err = tx.Exec("INSERT INTO modifier_group_links (group_id, product_id) VALUES ($1, $2)", group.ID, productID)
+		if err != nil {
+			return err
+		}
+	}
+	for _, categoryID := range group.CategoryIDs {
+		_, err = tx.Exec("INSERT INTO modifier_group_links (group_id, category_id) VALUES ($1, $2)", group.ID, categoryID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// productModifierGroups - grup yang di-link ke produk, kategorinya, atau salah satu induk kategorinya
+func productModifierGroups(q queryer, productID int) ([]models.ModifierGroup, error) {
+	query := "SELECT " + modifierGroupColumns + `
+		FROM modifier_groups g
+		WHERE EXISTS (
+			SELECT 1 FROM modifier_group_links l
+			WHERE l.group_id = g.id
+			  AND (l.product_id = $1 OR l.category_id IN ` + categoryAncestors("(SELECT category_id FROM products WHERE id = $1)") + `)
+		)
+		ORDER BY g.id
+	`
+	return modifierGroups(q, query, productID)
+}
+
+// modifierGroups - ambil grup beserta modifier-nya
+func modifierGroups(q queryer, query string, args ...interface{}) ([]models.ModifierGroup, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]models.ModifierGroup, 0)
+	for rows.Next() {
+		var g models.ModifierGroup
+		var productIDs, categoryIDs pq.Int64Array
+		err := rows.Scan(&g.ID, &g.Name, &g.Required, &g.MinSelect, &g.MaxSelect, &productIDs, &categoryIDs)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		g.ProductIDs = toInts(productIDs)
+		g.CategoryIDs = toInts(categoryIDs)
+		groups = append(groups, g)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		groups[i].Modifiers, err = groupModifiers(q, groups[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
+
+func groupModifiers(q queryer, groupID int) ([]models.Modifier, error) {
+	rows, err := q.Query("SELECT id, group_id, name, price_delta FROM modifiers WHERE group_id = $1 ORDER BY id", groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modifiers := make([]models.Modifier, 0)
+	for rows.Next() {
+		var m models.Modifier
+		err := rows.Scan(&m.ID, &m.GroupID, &m.Name, &m.PriceDelta)
+		if err != nil {
+			return nil, err
+		}
+		modifiers = append(modifiers, m)
+	}
+
+	return modifiers, rows.Err()
+}
+
+// selectModifiers - validasi pilihan modifier terhadap aturan tiap grup
+func selectModifiers(groups []models.ModifierGroup, selected []int) ([]models.DetailModifier, error) {
+	chosen := map[int]bool{}
+	for _, id := range selected {
+		if chosen[id] {
+			return nil, models.NewValidationError("modifier id %d selected more than once", id)
+		}
+		chosen[id] = true
+	}
+
+	result := make([]models.DetailModifier, 0, len(selected))
+	for _, g := range groups {
+		count := 0
+		for _, m := range g.Modifiers {
+			if !chosen[m.ID] {
+				continue
+			}
+			count++
+			delete(chosen, m.ID)
+			result = append(result, models.DetailModifier{ModifierID: m.ID, Name: m.Name, PriceDelta: m.PriceDelta})
+		}
+
+		min := g.MinSelect
+		if g.Required && min < 1 {
+			min = 1
+		}
+		if (g.Required || count > 0) && count < min {
+			return nil, models.NewValidationError("modifier group %q needs at least %d selection(s)", g.Name, min)
+		}
+		if g.MaxSelect > 0 && count > g.MaxSelect {
+			return nil, models.NewValidationError("modifier group %q allows at most %d selection(s)", g.Name, g.MaxSelect)
+		}
+	}
+
+	for id := range chosen {
+		return nil, models.NewValidationError("modifier id %d is not available for this product", id)
+	}
+
+	return result, nil
+}
+
+func toInts(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
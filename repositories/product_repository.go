@@ -2,8 +2,15 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"kasir-api/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 type ProductRepository struct {
@@ -14,23 +21,82 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (repo *ProductRepository) GetAll(nameFilter string) ([]models.Product, error) {
+// stok bundle = jumlah paket yang bisa dibuat dari stok komponen,
+// harga modal bundle = total harga modal komponennya
+const (
+	productStockColumn = `COALESCE((
+		SELECT MIN(cp.stock / pc.quantity)
+		FROM product_components pc
+		JOIN products cp ON cp.id = pc.component_id
+		WHERE pc.bundle_id = p.id
+	), p.stock)`
+	productCostColumn = `COALESCE((
+		SELECT SUM(cp.cost_price * pc.quantity)
+		FROM product_components pc
+		JOIN products cp ON cp.id = pc.component_id
+		WHERE pc.bundle_id = p.id
+	), p.cost_price)`
+	productIsBundleColumn = "EXISTS (SELECT 1 FROM product_components pc WHERE pc.bundle_id = p.id)"
+)
+
+// productSortColumns - kolom sort yang diizinkan untuk listing produk
+var productSortColumns = map[string]string{
+	"name":       "p.name",
+	"price":      "p.price",
+	"stock":      productStockColumn,
+	"updated_at": "p.updated_at",
+}
+
+// GetAll - listing produk dengan filter, sort dan offset pagination, total = jumlah semua baris yang lolos filter
+func (repo *ProductRepository) GetAll(filter models.ProductFilter) ([]models.Product, int, error) {
+	where := " WHERE 1 = 1"
+	args := []interface{}{}
+	if filter.Name != "" {
+		args = append(args, "%"+filter.Name+"%")
+		where += " AND p.name ILIKE $" + strconv.Itoa(len(args))
+	}
+	if filter.CategoryID > 0 {
+		args = append(args, filter.CategoryID)
+		if filter.DirectCategory {
+			where += " AND p.category_id = $" + strconv.Itoa(len(args))
+		} else {
+			where += " AND p.category_id IN " + categorySubtree("$"+strconv.Itoa(len(args)))
+		}
+	}
+	if filter.MinPrice != nil {
+		args = append(args, *filter.MinPrice)
+		where += " AND p.price >= $" + strconv.Itoa(len(args))
+	}
+	if filter.MaxPrice != nil {
+		args = append(args, *filter.MaxPrice)
+		where += " AND p.price <= $" + strconv.Itoa(len(args))
+	}
+	if filter.InStock {
+		where += " AND " + productStockColumn + " > 0"
+	}
+	where += metadataFilter(filter.Tags, filter.Attributes, filter.NumericAttributes, &args)
+	where += archivedFilter("p", filter.Status)
+
+	var total int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM products p"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
 	query :=
 		`
-			SELECT p.id, p.name, p.price, p.stock, c.name as category_name 
+			SELECT p.id, p.name, p.price, ` + productCostColumn + `, ` + productStockColumn + `, COALESCE(p.barcode, ''), COALESCE(p.sku, ''), ` + productIsBundleColumn + `, p.lot_policy, p.allow_expired_sale, p.min_stock, p.reorder_qty, p.base_unit,
+				p.version, p.updated_at, p.archived_at, COALESCE(p.category_id, 0), COALESCE(c.name, '') as category_name,
+				COALESCE(p.image_key, ''), COALESCE(p.image_medium_key, ''), COALESCE(p.image_thumb_key, ''), p.tags, p.attributes
 			FROM products p
-			JOIN categories c ON p.category_id = c.id
-		`
+			LEFT JOIN categories c ON p.category_id = c.id
+		` + where + " ORDER BY " + orderBy(productSortColumns, filter.Sort, filter.Desc, "p.id")
 
-	args := []interface{}{}
-	if nameFilter != "" {
-		query += " WHERE p.name ILIKE $1"
-		args = append(args, "%"+nameFilter+"%")
-	}
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
 
 	rows, err := repo.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -38,42 +104,260 @@ func (repo *ProductRepository) GetAll(nameFilter string) ([]models.Product, erro
 	for rows.Next() {
 		var p models.Product
 		var categoryName string
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &categoryName)
+		var archivedAt sql.NullTime
+		var attributes []byte
+		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.CostPrice, &p.Stock, &p.Barcode, &p.SKU, &p.IsBundle, &p.LotPolicy, &p.AllowExpiredSale, &p.MinStock, &p.ReorderQty, &p.BaseUnit,
+			&p.Version, &p.UpdatedAt, &archivedAt, &p.CategoryID, &categoryName, &p.ImageURL, &p.ImageMediumURL, &p.ImageThumbURL, pq.Array(&p.Tags), &attributes)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+		if err := scanMetadata(&p, attributes); err != nil {
+			return nil, 0, err
 		}
 		p.CategoryName = categoryName
+		if archivedAt.Valid {
+			p.ArchivedAt = &archivedAt.Time
+		}
 		products = append(products, p)
 	}
 
-	return products, nil
+	return products, total, rows.Err()
 }
 
-func (repo *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock) VALUES ($1, $2, $3) RETURNING id"
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock).Scan(&product.ID)
-	return err
+func (repo *ProductRepository) Create(product *models.Product, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertProduct(tx, product, user, "initial stock"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetByID - ambil produk by ID
+// GetByID - ambil produk by ID, termasuk yang diarsip
 func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
-	query := "SELECT id, name, price, stock FROM products WHERE id = $1"
+	query := "SELECT p.id, p.name, p.price, " + productCostColumn + ", " + productStockColumn + ", COALESCE(p.barcode, ''), COALESCE(p.sku, ''), " + productIsBundleColumn + ", p.lot_policy, p.allow_expired_sale, p.min_stock, p.reorder_qty, p.base_unit, p.version, p.updated_at, p.archived_at, COALESCE(p.category_id, 0), " +
+		"COALESCE(p.image_key, ''), COALESCE(p.image_medium_key, ''), COALESCE(p.image_thumb_key, ''), p.tags, p.attributes FROM products p WHERE p.id = $1"
 
 	var p models.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock)
+	var archivedAt sql.NullTime
+	var attributes []byte
+	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.CostPrice, &p.Stock, &p.Barcode, &p.SKU, &p.IsBundle, &p.LotPolicy, &p.AllowExpiredSale, &p.MinStock, &p.ReorderQty, &p.BaseUnit,
+		&p.Version, &p.UpdatedAt, &archivedAt, &p.CategoryID, &p.ImageURL, &p.ImageMediumURL, &p.ImageThumbURL, pq.Array(&p.Tags), &attributes)
 	if err == sql.ErrNoRows {
 		return nil, errors.New("Product not found")
 	}
 	if err != nil {
 		return nil, err
 	}
+	if err := scanMetadata(&p, attributes); err != nil {
+		return nil, err
+	}
+	if archivedAt.Valid {
+		p.ArchivedAt = &archivedAt.Time
+	}
 
 	return &p, nil
 }
 
-func (repo *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3 WHERE id = $4"
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.ID)
+// Update - perubahan stok lewat update produk dicatat sebagai adjustment
+func (repo *ProductRepository) Update(product *models.Product, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateProduct(tx, product, user, "stock set via product update"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Patch - tulis hanya kolom yang dikirim di PATCH, stok hanya disentuh kalau kolom "stock" ikut dan dicatat sebagai adjustment
+func (repo *ProductRepository) Patch(product *models.Product, columns []string, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldStock, version int
+	err = tx.QueryRow("SELECT stock, version FROM products WHERE id = $1 FOR UPDATE", product.ID).Scan(&oldStock, &version)
+	if err == sql.ErrNoRows {
+		return errors.New("Product not found")
+	}
+	if err != nil {
+		return err
+	}
+	if product.Version > 0 && product.Version != version {
+		return models.ErrVersionConflict
+	}
+
+	args := []interface{}{}
+	sets := make([]string, 0, len(columns))
+	patchStock := false
+	for _, column := range columns {
+		if column == "barcode" {
+			if err := checkUnitBarcode(tx, product.Barcode); err != nil {
+				return err
+			}
+		}
+		expr, value, err := productPatchColumn(product, column)
+		if err != nil {
+			return err
+		}
+		args = append(args, value)
+		sets = append(sets, column+" = "+strings.Replace(expr, "?", "$"+strconv.Itoa(len(args)), 1))
+		patchStock = patchStock || column == "stock"
+	}
+	args = append(args, product.ID)
+	query := "UPDATE products SET " + strings.Join(sets, ", ") + ", updated_at = NOW(), version = version + 1 WHERE id = $" + strconv.Itoa(len(args)) + " RETURNING version"
+	if err := tx.QueryRow(query, args...).Scan(&product.Version); err != nil {
+		return err
+	}
+
+	if patchStock {
+		err := recordMovement(tx, &models.StockMovement{
+			ProductID:     product.ID,
+			QuantityDelta: product.Stock - oldStock,
+			Reason:        models.MovementAdjustment,
+			User:          user,
+			Note:          "stock set via product patch",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// productPatchColumn - ekspresi SET (? = placeholder) dan nilai untuk satu kolom PATCH
+func productPatchColumn(p *models.Product, column string) (string, interface{}, error) {
+	switch column {
+	case "name":
+		return "?", p.Name, nil
+	case "price":
+		return "?", p.Price, nil
+	case "cost_price":
+		return "?", p.CostPrice, nil
+	case "stock":
+		return "?", p.Stock, nil
+	case "barcode":
+		return "NULLIF(?, '')", p.Barcode, nil
+	case "sku":
+		return "NULLIF(?, '')", p.SKU, nil
+	case "category_id":
+		return "NULLIF(?::int, 0)", p.CategoryID, nil
+	case "lot_policy":
+		return "?", p.LotPolicy, nil
+	case "allow_expired_sale":
+		return "?", p.AllowExpiredSale, nil
+	case "min_stock":
+		return "?", p.MinStock, nil
+	case "reorder_qty":
+		return "?", p.ReorderQty, nil
+	case "base_unit":
+		return "?", p.BaseUnit, nil
+	case "tags":
+		return "COALESCE(?::text[], '{}')", pq.Array(p.Tags), nil
+	case "attributes":
+		attributes, err := attributesJSON(p.Attributes)
+		return "?::jsonb", attributes, err
+	default:
+		return "", nil, fmt.Errorf("column %s cannot be patched", column)
+	}
+}
+
+// Archive - soft delete, produk hilang dari POS tapi tetap ada di histori dan laporan. version 0 = tanpa cek version
+func (repo *ProductRepository) Archive(id int, version int) error {
+	return setArchived(repo.db, "products", id, version, true, "Product not found or already archived")
+}
+
+func (repo *ProductRepository) Restore(id int, version int) error {
+	return setArchived(repo.db, "products", id, version, false, "Product not found or not archived")
+}
+
+// Purge - hapus permanen produk yang sudah diarsip dan belum punya histori, balikan key gambar untuk dihapus dari storage.
+// Ledger stock_movements sengaja tanpa foreign key jadi tidak dihitung sebagai histori
+func (repo *ProductRepository) Purge(id int, version int) ([]string, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	var current int
+	var original, medium, thumb string
+	err = tx.QueryRow("SELECT archived_at IS NOT NULL, version, COALESCE(image_key, ''), COALESCE(image_medium_key, ''), COALESCE(image_thumb_key, '') FROM products WHERE id = $1 FOR UPDATE", id).
+		Scan(&archived, &current, &original, &medium, &thumb)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 && version != current {
+		return nil, models.ErrVersionConflict
+	}
+	if !archived {
+		return nil, errors.New("only archived products can be purged")
+	}
+
+	var history, component bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM transaction_details WHERE product_id = $1)
+			OR EXISTS (SELECT 1 FROM transaction_detail_components WHERE product_id = $1)
+			OR EXISTS (SELECT 1 FROM tab_items WHERE product_id = $1)
+			OR EXISTS (SELECT 1 FROM purchase_order_items WHERE product_id = $1)
+			OR EXISTS (SELECT 1 FROM stock_take_lines WHERE product_id = $1)
+			OR EXISTS (SELECT 1 FROM stock_lots WHERE product_id = $1),
+			EXISTS (SELECT 1 FROM product_components WHERE component_id = $1)
+	`, id).Scan(&history, &component)
+	if err != nil {
+		return nil, err
+	}
+	if history {
+		return nil, errors.New("product has sales, purchase or stock history and can only stay archived")
+	}
+	if component {
+		return nil, errors.New("product is a component of a bundle, remove it from the bundle first")
+	}
+
+	if _, err := tx.Exec("DELETE FROM products WHERE id = $1", id); err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, errors.New("product is still referenced and can only stay archived")
+		}
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, 3)
+	for _, key := range []string{original, medium, thumb} {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// setArchived - arsip/pulihkan satu baris table, 0 baris = tidak ketemu, version beda, atau status sudah sesuai
+func setArchived(db *sql.DB, table string, id int, version int, archive bool, notFound string) error {
+	set, condition := "archived_at = NOW()", "archived_at IS NULL"
+	if !archive {
+		set, condition = "archived_at = NULL", "archived_at IS NOT NULL"
+	}
+	query := "UPDATE " + table + " SET " + set + ", updated_at = NOW(), version = version + 1 WHERE id = $1 AND " + condition + " AND ($2::int = 0 OR version = $2)"
+	result, err := db.Exec(query, id, version)
 	if err != nil {
 		return err
 	}
@@ -84,26 +368,451 @@ func (repo *ProductRepository) Update(product *models.Product) error {
 	}
 
 	if rows == 0 {
-		return errors.New("Product not found")
+		return checkVersion(db, table, id, version, notFound)
 	}
 
 	return nil
 }
 
-func (repo *ProductRepository) Delete(id int) error {
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+// checkVersion - error untuk update yang tidak mengenai baris: ErrVersionConflict kalau version sudah berubah,
+// selain itu notFound. version 0 = tanpa cek version
+func checkVersion(q rowQueryer, table string, id int, version int, notFound string) error {
+	var current int
+	err := q.QueryRow("SELECT version FROM "+table+" WHERE id = $1", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return errors.New(notFound)
+	}
+	if err != nil {
+		return err
+	}
+	if version > 0 && version != current {
+		return models.ErrVersionConflict
+	}
+	return errors.New(notFound)
+}
+
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// SetBarcode - simpan barcode, hanya kalau produk belum punya barcode
+func (repo *ProductRepository) SetBarcode(id int, code string) error {
+	query := `
+		UPDATE products SET barcode = $1, updated_at = NOW(), version = version + 1
+		WHERE id = $2 AND barcode IS NULL AND NOT EXISTS (SELECT 1 FROM product_units WHERE barcode = $1)
+	`
+	result, err := repo.db.Exec(query, code, id)
 	if err != nil {
 		return err
 	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
 	if rows == 0 {
+		return errors.New("Product not found, already has a barcode or barcode is used by a unit")
+	}
+
+	return nil
+}
+
+// GetWithoutBarcode - produk aktif yang belum punya barcode
+func (repo *ProductRepository) GetWithoutBarcode() ([]models.Product, error) {
+	query := "SELECT id, name, price, stock FROM products WHERE barcode IS NULL AND archived_at IS NULL ORDER BY id"
+	rows, err := repo.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
+// GetForLabels - produk berdasarkan daftar ID dan/atau kategori (termasuk sub kategori), untuk cetak label
+func (repo *ProductRepository) GetForLabels(ids []int, categoryID int) ([]models.Product, error) {
+	query := `
+		SELECT id, name, price, stock, COALESCE(barcode, '')
+		FROM products
+		WHERE (cardinality($1::int[]) > 0 AND id = ANY($1))
+		   OR ($2 > 0 AND archived_at IS NULL AND category_id IN ` + categorySubtree("$2") + `)
+		ORDER BY name
+	`
+	rows, err := repo.db.Query(query, pq.Array(ids), categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	for rows.Next() {
+		var p models.Product
+		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.Barcode)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, nil
+}
+
+// metadataFilter - kondisi tag (harus punya semua) dan nilai atribut (tidak case-sensitive, key di numeric
+// dibandingkan sebagai angka supaya "5" = "5.0"), args ditambah di tempat
+func metadataFilter(tags []string, attributes map[string]string, numeric map[string]bool, args *[]interface{}) string {
+	where := ""
+	if len(tags) > 0 {
+		*args = append(*args, pq.Array(tags))
+		where += " AND p.tags @> $" + strconv.Itoa(len(*args)) + "::text[]"
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		*args = append(*args, key, attributes[key])
+		k, v := strconv.Itoa(len(*args)-1), strconv.Itoa(len(*args))
+		if numeric[key] {
+			where += " AND CASE WHEN jsonb_typeof(p.attributes -> $" + k + ") = 'number' THEN (p.attributes ->> $" + k + ")::numeric END = $" + v + "::numeric"
+			continue
+		}
+		where += " AND LOWER(p.attributes ->> $" + k + ") = LOWER($" + v + ")"
+	}
+	return where
+}
+
+// scanMetadata - tags kosong tetap [] di JSON, attributes dari kolom jsonb
+func scanMetadata(p *models.Product, attributes []byte) error {
+	if p.Tags == nil {
+		p.Tags = []string{}
+	}
+	p.Attributes = map[string]interface{}{}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(attributes, &p.Attributes)
+}
+
+// attributesJSON - nilai kolom attributes, nil = object kosong
+func attributesJSON(attributes map[string]interface{}) (string, error) {
+	if len(attributes) == 0 {
+		return "{}", nil
+	}
+	data, err := json.Marshal(attributes)
+	return string(data), err
+}
+
+// archivedFilter - kondisi archived_at untuk status listing, selain archived/all dianggap active
+func archivedFilter(alias string, status string) string {
+	switch status {
+	case models.ListAll:
+		return ""
+	case models.ListArchived:
+		return " AND " + alias + ".archived_at IS NOT NULL"
+	default:
+		return " AND " + alias + ".archived_at IS NULL"
+	}
+}
+
+// orderBy - klausa ORDER BY dari daftar kolom yang diizinkan, tieBreaker supaya urutan antar halaman stabil
+func orderBy(columns map[string]string, sort string, desc bool, tieBreaker string) string {
+	column, ok := columns[sort]
+	if !ok {
+		column = columns["name"]
+	}
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	return column + direction + ", " + tieBreaker + direction
+}
+
+// Search - pencarian fuzzy (trigram pg_trgm) di nama, SKU, barcode, SKU varian, nama kategori dan tag.
+// Urut berdasarkan kemiripan lalu qty terjual 30 hari terakhir, hanya produk aktif.
+// tags/attributes mempersempit hasil seperti filter di GetAll
+func (repo *ProductRepository) Search(q string, limit int, tags []string, attributes map[string]string, numeric map[string]bool) ([]models.ProductSearchResult, error) {
+	args := []interface{}{q, limit}
+	filter := metadataFilter(tags, attributes, numeric, &args)
+
+	query := `
+		WITH matches AS (
+			SELECT p.id, COALESCE(c.name, '') AS category_name, COALESCE(vs.sku, '') AS matched_sku,
+				GREATEST(
+					similarity(p.name, $1),
+					word_similarity($1, p.name),
+					CASE WHEN p.name ILIKE $1 || '%' THEN 0.9 WHEN p.name ILIKE '%' || $1 || '%' THEN 0.7 ELSE 0 END,
+					CASE WHEN p.barcode = $1 OR p.sku ILIKE $1 OR vs.sku ILIKE $1 THEN 1 ELSE 0 END,
+					CASE WHEN p.barcode LIKE $1 || '%' OR p.sku ILIKE $1 || '%' OR vs.sku ILIKE $1 || '%' THEN 0.8 ELSE 0 END,
+					CASE WHEN LOWER($1) = ANY(p.tags) THEN 0.9 ELSE 0 END,
+					similarity(COALESCE(c.name, ''), $1) * 0.5
+				) AS relevance
+			FROM products p
+			LEFT JOIN categories c ON c.id = p.category_id
+			LEFT JOIN LATERAL (
+				SELECT v.sku FROM product_variants v
+				WHERE v.product_id = p.id AND (v.sku % $1 OR v.sku ILIKE $1 || '%')
+				ORDER BY similarity(v.sku, $1) DESC
+				LIMIT 1
+			) vs ON true
+			WHERE p.archived_at IS NULL
+				AND (p.name % $1 OR $1 <% p.name OR p.name ILIKE '%' || $1 || '%'
+					OR p.sku % $1 OR p.sku ILIKE $1 || '%' OR p.barcode LIKE $1 || '%'
+					OR vs.sku IS NOT NULL OR c.name % $1 OR LOWER($1) = ANY(p.tags))` + filter + `
+		), popularity AS (
+			SELECT d.product_id, SUM(d.base_quantity) AS qty
+			FROM transaction_details d
+			JOIN transactions t ON t.id = d.transaction_id
+			WHERE t.created_at >= NOW() - INTERVAL '30 days' AND d.product_id IN (SELECT id FROM matches)
+			GROUP BY d.product_id
+		)
+		SELECT p.id, p.name, COALESCE(p.sku, ''), COALESCE(p.barcode, ''), p.price, ` + productStockColumn + `, m.category_name, m.matched_sku,
+			p.tags, COALESCE(p.image_thumb_key, ''), COALESCE(pop.qty, 0), m.relevance + LN(1 + COALESCE(pop.qty, 0)) / 100
+		FROM matches m
+		JOIN products p ON p.id = m.id
+		LEFT JOIN popularity pop ON pop.product_id = m.id
+		ORDER BY 12 DESC, p.name
+		LIMIT $2
+	`
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]models.ProductSearchResult, 0)
+	for rows.Next() {
+		var r models.ProductSearchResult
+		err := rows.Scan(&r.ID, &r.Name, &r.SKU, &r.Barcode, &r.Price, &r.Stock, &r.CategoryName, &r.MatchedSKU, pq.Array(&r.Tags), &r.ImageThumbURL, &r.QtySold, &r.Score)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
+
+// insertProduct - simpan produk baru, stok awal dicatat sebagai adjustment dengan note
+func insertProduct(tx *sql.Tx, product *models.Product, user string, note string) error {
+	query := `
+		INSERT INTO products (name, price, cost_price, stock, barcode, lot_policy, allow_expired_sale, min_stock, reorder_qty, base_unit, category_id, sku, tags, attributes)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, NULLIF($11::int, 0), NULLIF($12, ''), COALESCE($13::text[], '{}'), $14)
+		RETURNING id, version
+	`
+	if err := checkUnitBarcode(tx, product.Barcode); err != nil {
+		return err
+	}
+	attributes, err := attributesJSON(product.Attributes)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow(query, product.Name, product.Price, product.CostPrice, product.Stock, product.Barcode, product.LotPolicy, product.AllowExpiredSale,
+		product.MinStock, product.ReorderQty, product.BaseUnit, product.CategoryID, product.SKU, pq.Array(product.Tags), attributes).Scan(&product.ID, &product.Version)
+	if err != nil {
+		return err
+	}
+
+	return recordMovement(tx, &models.StockMovement{
+		ProductID:     product.ID,
+		QuantityDelta: product.Stock,
+		Reason:        models.MovementAdjustment,
+		User:          user,
+		Note:          note,
+	})
+}
+
+// checkUnitBarcode - kebalikan cek di UnitRepository: barcode produk tidak boleh sama dengan barcode satuan mana pun
+func checkUnitBarcode(q rowQueryer, code string) error {
+	if code == "" {
+		return nil
+	}
+	var used bool
+	if err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM product_units WHERE barcode = $1)", code).Scan(&used); err != nil {
+		return err
+	}
+	if used {
+		return fmt.Errorf("barcode %s is already used by a product unit", code)
+	}
+	return nil
+}
+
+// updateProduct - simpan semua kolom produk, selisih stok dicatat sebagai adjustment dengan note.
+// Stok dan harga modal bundle turunan dari komponen, nilai tersimpan dipertahankan
+func updateProduct(tx *sql.Tx, product *models.Product, user string, note string) error {
+	var oldStock, oldCost, version int
+	var isBundle bool
+	err := tx.QueryRow("SELECT p.stock, p.cost_price, p.version, "+productIsBundleColumn+" FROM products p WHERE p.id = $1 FOR UPDATE", product.ID).
+		Scan(&oldStock, &oldCost, &version, &isBundle)
+	if err == sql.ErrNoRows {
 		return errors.New("Product not found")
 	}
+	if err != nil {
+		return err
+	}
+	if product.Version > 0 && product.Version != version {
+		return models.ErrVersionConflict
+	}
+	if isBundle {
+		product.Stock, product.CostPrice = oldStock, oldCost
+	}
+
+	query := `
+		UPDATE products
+		SET name = $1, price = $2, cost_price = $3, stock = $4, barcode = NULLIF($5, ''), lot_policy = $6, allow_expired_sale = $7,
+			min_stock = $8, reorder_qty = $9, base_unit = $10, category_id = NULLIF($11::int, 0),
+			sku = NULLIF($12, ''), tags = COALESCE($13::text[], '{}'), attributes = $14, updated_at = NOW(), version = version + 1
+		WHERE id = $15
+		RETURNING version
+	`
+	if err := checkUnitBarcode(tx, product.Barcode); err != nil {
+		return err
+	}
+	attributes, err := attributesJSON(product.Attributes)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow(query, product.Name, product.Price, product.CostPrice, product.Stock, product.Barcode, product.LotPolicy, product.AllowExpiredSale,
+		product.MinStock, product.ReorderQty, product.BaseUnit, product.CategoryID, product.SKU, pq.Array(product.Tags), attributes, product.ID).Scan(&product.Version)
+	if err != nil {
+		return err
+	}
+
+	return recordMovement(tx, &models.StockMovement{
+		ProductID:     product.ID,
+		QuantityDelta: product.Stock - oldStock,
+		Reason:        models.MovementAdjustment,
+		User:          user,
+		Note:          note,
+	})
+}
+
+// GetBySKUs - produk berdasarkan SKU untuk upsert import, stok dan harga modal mentah (bukan turunan bundle)
+func (repo *ProductRepository) GetBySKUs(skus []string) (map[string]models.Product, error) {
+	query := `
+		SELECT p.id, p.name, p.price, p.cost_price, p.stock, COALESCE(p.barcode, ''), p.sku, ` + productIsBundleColumn + `, p.lot_policy, p.allow_expired_sale,
+			p.min_stock, p.reorder_qty, p.base_unit, COALESCE(p.category_id, 0), p.tags, p.attributes
+		FROM products p
+		WHERE p.sku = ANY($1)
+	`
+	rows, err := repo.db.Query(query, pq.Array(skus))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[string]models.Product)
+	for rows.Next() {
+		var p models.Product
+		var attributes []byte
+		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.CostPrice, &p.Stock, &p.Barcode, &p.SKU, &p.IsBundle, &p.LotPolicy, &p.AllowExpiredSale,
+			&p.MinStock, &p.ReorderQty, &p.BaseUnit, &p.CategoryID, pq.Array(&p.Tags), &attributes)
+		if err != nil {
+			return nil, err
+		}
+		if err := scanMetadata(&p, attributes); err != nil {
+			return nil, err
+		}
+		products[p.SKU] = p
+	}
+
+	return products, rows.Err()
+}
 
-	return err
+// GetBarcodeOwners - barcode -> id produk pemiliknya, barcode satuan dianggap milik produk id 0
+func (repo *ProductRepository) GetBarcodeOwners(barcodes []string) (map[string]int, error) {
+	rows, err := repo.db.Query(`
+		SELECT barcode, id FROM products WHERE barcode = ANY($1)
+		UNION ALL
+		SELECT barcode, 0 FROM product_units WHERE barcode = ANY($1)
+	`, pq.Array(barcodes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	owners := make(map[string]int)
+	for rows.Next() {
+		var code string
+		var id int
+		if err := rows.Scan(&code, &id); err != nil {
+			return nil, err
+		}
+		owners[code] = id
+	}
+
+	return owners, rows.Err()
+}
+
+// Import - simpan semua baris import dalam satu transaksi, gagal satu batal semua
+func (repo *ProductRepository) Import(rows []models.ProductImportRow, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		if row.Action == "create" {
+			err = insertProduct(tx, row.Product, user, "initial stock via import")
+		} else {
+			err = updateProduct(tx, row.Product, user, "stock set via import")
+		}
+		if isUniqueViolation(err) {
+			return fmt.Errorf("row %d: sku or barcode already exists", row.Row)
+		}
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row.Row, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetImage - ganti key gambar produk (kosong = hapus gambar), mengembalikan key lama supaya filenya bisa dihapus
+func (repo *ProductRepository) SetImage(id int, original string, medium string, thumb string) ([]string, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old [3]string
+	err = tx.QueryRow("SELECT COALESCE(image_key, ''), COALESCE(image_medium_key, ''), COALESCE(image_thumb_key, '') FROM products WHERE id = $1 FOR UPDATE", id).
+		Scan(&old[0], &old[1], &old[2])
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("UPDATE products SET image_key = NULLIF($1, ''), image_medium_key = NULLIF($2, ''), image_thumb_key = NULLIF($3, ''), updated_at = NOW(), version = version + 1 WHERE id = $4",
+		original, medium, thumb, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(old))
+	for _, k := range old {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
 }
@@ -0,0 +1,487 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"strconv"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `
+	SELECT po.id, po.supplier_id, s.name, po.status, po.note, po.expected_at, po.created_by, po.created_at, po.ordered_at,
+		COALESCE((SELECT SUM(i.quantity * i.unit_cost) FROM purchase_order_items i WHERE i.purchase_order_id = po.id), 0)
+	FROM purchase_orders po
+	JOIN suppliers s ON s.id = po.supplier_id
+`
+
+func (repo *PurchaseOrderRepository) GetAll(status string, supplierID int) ([]models.PurchaseOrder, error) {
+	query := purchaseOrderColumns + " WHERE 1 = 1"
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		query += " AND po.status = $" + strconv.Itoa(len(args))
+	}
+	if supplierID > 0 {
+		args = append(args, supplierID)
+		query += " AND po.supplier_id = $" + strconv.Itoa(len(args))
+	}
+	query += " ORDER BY po.created_at DESC, po.id DESC"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *po)
+	}
+
+	return orders, nil
+}
+
+// GetByID - PO beserta item dan semua penerimaan barangnya
+func (repo *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(repo.db.QueryRow(purchaseOrderColumns+" WHERE po.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Purchase order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	po.Items, err = purchaseOrderItems(repo.db, id)
+	if err != nil {
+		return nil, err
+	}
+	po.Receipts, err = repo.getReceipts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+// Create - PO baru selalu berstatus draft
+func (repo *PurchaseOrderRepository) Create(req models.PurchaseOrderRequest, user string) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow("INSERT INTO purchase_orders (supplier_id, note, expected_at, created_by) VALUES ($1, $2, NULLIF($3, '')::date, $4) RETURNING id",
+		req.SupplierID, req.Note, req.ExpectedAt, user).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertPurchaseOrderItems(tx, id, req.Items); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update - hanya PO draft yang bisa diubah, item lama diganti semua
+func (repo *PurchaseOrderRepository) Update(id int, req models.PurchaseOrderRequest) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, models.POStatusDraft); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET supplier_id = $1, note = $2, expected_at = NULLIF($3, '')::date WHERE id = $4",
+		req.SupplierID, req.Note, req.ExpectedAt, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM purchase_order_items WHERE purchase_order_id = $1", id); err != nil {
+		return err
+	}
+	if err := insertPurchaseOrderItems(tx, id, req.Items); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkOrdered - draft -> ordered, PO dianggap sudah dikirim ke supplier
+func (repo *PurchaseOrderRepository) MarkOrdered(id int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, models.POStatusDraft); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1, ordered_at = NOW() WHERE id = $2", models.POStatusOrdered, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Cancel - barang yang sudah diterima tetap masuk stok, sisanya tidak ditunggu lagi
+func (repo *PurchaseOrderRepository) Cancel(id int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, models.POStatusDraft, models.POStatusOrdered, models.POStatusPartiallyReceived); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", models.POStatusCancelled, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive - catat GRN, tambah stok sebagai lot baru, perbarui harga modal (rata-rata tertimbang) lalu status PO
+func (repo *PurchaseOrderRepository) Receive(id int, req models.GoodsReceiptRequest, user string) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(tx, id, models.POStatusOrdered, models.POStatusPartiallyReceived); err != nil {
+		return 0, err
+	}
+
+	var receiptID int
+	err = tx.QueryRow("INSERT INTO goods_receipts (purchase_order_id, note, received_by) VALUES ($1, $2, $3) RETURNING id", id, req.Note, user).Scan(&receiptID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range req.Items {
+		var productID, variantID, factor, quantity, receivedQty, unitCost int
+		err := tx.QueryRow(`
+			SELECT product_id, COALESCE(variant_id, 0), unit_factor, quantity, received_qty, unit_cost
+			FROM purchase_order_items
+			WHERE id = $1 AND purchase_order_id = $2
+			FOR UPDATE
+		`, item.PurchaseOrderItemID, id).Scan(&productID, &variantID, &factor, &quantity, &receivedQty, &unitCost)
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("purchase order item id %d not found", item.PurchaseOrderItemID)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if item.Quantity <= 0 {
+			return 0, fmt.Errorf("quantity for purchase order item id %d must be greater than 0", item.PurchaseOrderItemID)
+		}
+		if item.Quantity > quantity-receivedQty {
+			return 0, fmt.Errorf("purchase order item id %d only has %d left to receive", item.PurchaseOrderItemID, quantity-receivedQty)
+		}
+		if item.UnitCost != nil {
+			if *item.UnitCost < 0 {
+				return 0, errors.New("unit_cost cannot be negative")
+			}
+			unitCost = *item.UnitCost
+		}
+
+		_, err = tx.Exec("INSERT INTO goods_receipt_items (goods_receipt_id, purchase_order_item_id, quantity, unit_cost) VALUES ($1, $2, $3, $4)",
+			receiptID, item.PurchaseOrderItemID, item.Quantity, unitCost)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec("UPDATE purchase_order_items SET received_qty = received_qty + $1 WHERE id = $2", item.Quantity, item.PurchaseOrderItemID)
+		if err != nil {
+			return 0, err
+		}
+
+		// qty dan harga di PO dalam satuan beli (misal karton), stok dan harga modal dalam satuan dasar
+		baseQty := item.Quantity * factor
+		baseCost := (unitCost + factor/2) / factor
+
+		// rata-rata tertimbang dengan stok lama, dihitung sebelum stok bertambah.
+		// Stok minus dianggap 0 supaya harga modal tidak melonjak
+		_, err = tx.Exec(`
+			UPDATE products
+			SET cost_price = ROUND((GREATEST(stock, 0) * cost_price + $1::numeric * $2) / (GREATEST(stock, 0) + $1))
+			WHERE id = $3
+		`, baseQty, baseCost, productID)
+		if err != nil {
+			return 0, err
+		}
+
+		err = changeStock(tx, &models.StockMovement{
+			ProductID:     productID,
+			VariantID:     variantID,
+			QuantityDelta: baseQty,
+			Reason:        models.MovementReceiving,
+			ReferenceType: "goods_receipt",
+			ReferenceID:   receiptID,
+			User:          user,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = createLot(tx, productID, variantID, receiptID, item.LotCode, item.ExpiryDate, baseCost, baseQty)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchase_orders
+		SET status = CASE
+			WHEN (SELECT bool_and(received_qty = quantity) FROM purchase_order_items WHERE purchase_order_id = $1) THEN $2
+			ELSE $3
+		END
+		WHERE id = $1
+	`, id, models.POStatusReceived, models.POStatusPartiallyReceived)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return receiptID, nil
+}
+
+// GetOutstanding - PO ordered/partially_received dikelompokkan per supplier
+func (repo *PurchaseOrderRepository) GetOutstanding(supplierID int) ([]models.OutstandingPO, error) {
+	query := purchaseOrderColumns + " WHERE po.status IN ($1, $2)"
+	args := []interface{}{models.POStatusOrdered, models.POStatusPartiallyReceived}
+	if supplierID > 0 {
+		query += " AND po.supplier_id = $3"
+		args = append(args, supplierID)
+	}
+	query += " ORDER BY s.name, po.supplier_id, po.expected_at NULLS LAST, po.id"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.OutstandingPO, 0)
+	for _, po := range orders {
+		items, err := purchaseOrderItems(repo.db, po.ID)
+		if err != nil {
+			return nil, err
+		}
+		po.Items = make([]models.PurchaseOrderItem, 0)
+		for _, item := range items {
+			if item.ReceivedQty < item.Quantity {
+				po.Items = append(po.Items, item)
+			}
+		}
+
+		if len(result) == 0 || result[len(result)-1].SupplierID != po.SupplierID {
+			result = append(result, models.OutstandingPO{SupplierID: po.SupplierID, SupplierName: po.SupplierName})
+		}
+		group := &result[len(result)-1]
+		group.Orders++
+		for _, item := range po.Items {
+			group.OutstandingQty += item.Quantity - item.ReceivedQty
+			group.OutstandingValue += (item.Quantity - item.ReceivedQty) * item.UnitCost
+		}
+		group.PurchaseOrders = append(group.PurchaseOrders, po)
+	}
+
+	return result, nil
+}
+
+func (repo *PurchaseOrderRepository) getReceipts(poID int) ([]models.GoodsReceipt, error) {
+	rows, err := repo.db.Query(`
+		SELECT r.id, r.note, r.received_by, r.received_at, ri.id, ri.purchase_order_item_id, i.product_id, COALESCE(i.variant_id, 0), ri.quantity, ri.unit_cost
+		FROM goods_receipts r
+		JOIN goods_receipt_items ri ON ri.goods_receipt_id = r.id
+		JOIN purchase_order_items i ON i.id = ri.purchase_order_item_id
+		WHERE r.purchase_order_id = $1
+		ORDER BY r.received_at, r.id, ri.id
+	`, poID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]models.GoodsReceipt, 0)
+	for rows.Next() {
+		var r models.GoodsReceipt
+		var item models.GoodsReceiptItem
+		err := rows.Scan(&r.ID, &r.Note, &r.ReceivedBy, &r.ReceivedAt, &item.ID, &item.PurchaseOrderItemID, &item.ProductID, &item.VariantID, &item.Quantity, &item.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		if len(receipts) == 0 || receipts[len(receipts)-1].ID != r.ID {
+			r.PurchaseOrderID = poID
+			receipts = append(receipts, r)
+		}
+		last := &receipts[len(receipts)-1]
+		last.Items = append(last.Items, item)
+	}
+
+	return receipts, nil
+}
+
+func insertPurchaseOrderItems(tx *sql.Tx, poID int, items []models.PurchaseOrderItemRequest) error {
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity for product id %d must be greater than 0", item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return fmt.Errorf("unit_cost for product id %d cannot be negative", item.ProductID)
+		}
+		if err := checkStockItem(tx, item.ProductID, item.VariantID); err != nil {
+			return err
+		}
+		unitName, factor, _, err := productUnit(tx, item.ProductID, item.UnitID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO purchase_order_items (purchase_order_id, product_id, variant_id, unit_id, unit_name, unit_factor, quantity, unit_cost)
+			VALUES ($1, $2, NULLIF($3::int, 0), NULLIF($4::int, 0), $5, $6, $7, COALESCE(NULLIF($8::int, 0), (SELECT cost_price FROM products WHERE id = $2) * $6))
+		`, poID, item.ProductID, item.VariantID, item.UnitID, unitName, factor, item.Quantity, item.UnitCost)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkStockItem - produk yang stoknya disimpan sendiri: bukan bundle, varian wajib kalau produk bervarian
+func checkStockItem(tx *sql.Tx, productID int, variantID int) error {
+	var isBundle, hasVariants bool
+	err := tx.QueryRow("SELECT "+productIsBundleColumn+", EXISTS (SELECT 1 FROM product_variants WHERE product_id = p.id) FROM products p WHERE p.id = $1", productID).Scan(&isBundle, &hasVariants)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product id %d not found", productID)
+	}
+	if err != nil {
+		return err
+	}
+	if isBundle {
+		return fmt.Errorf("product id %d is a bundle, use its components instead", productID)
+	}
+
+	if variantID > 0 {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE id = $1 AND product_id = $2)", variantID, productID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("variant id %d not found for product id %d", variantID, productID)
+		}
+	} else if hasVariants {
+		return fmt.Errorf("variant_id is required for product id %d", productID)
+	}
+
+	return nil
+}
+
+func lockPurchaseOrder(tx *sql.Tx, id int, allowed ...string) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("Purchase order id %d not found", id)
+	}
+	if err != nil {
+		return err
+	}
+	for _, s := range allowed {
+		if status == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("Purchase order id %d is %s", id, status)
+}
+
+func purchaseOrderItems(q queryer, poID int) ([]models.PurchaseOrderItem, error) {
+	rows, err := q.Query(`
+		SELECT i.id, i.product_id, p.name, COALESCE(i.variant_id, 0), COALESCE(v.name, ''), COALESCE(i.unit_id, 0), i.unit_name, i.unit_factor,
+			i.quantity, i.received_qty, i.unit_cost
+		FROM purchase_order_items i
+		JOIN products p ON p.id = i.product_id
+		LEFT JOIN product_variants v ON v.id = i.variant_id
+		WHERE i.purchase_order_id = $1
+		ORDER BY i.id
+	`, poID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.PurchaseOrderItem, 0)
+	for rows.Next() {
+		var item models.PurchaseOrderItem
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.VariantID, &item.VariantName, &item.UnitID, &item.UnitName, &item.UnitFactor,
+			&item.Quantity, &item.ReceivedQty, &item.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		item.Subtotal = item.Quantity * item.UnitCost
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func scanPurchaseOrder(row rowScanner) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	var expectedAt, orderedAt sql.NullTime
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &expectedAt, &po.CreatedBy, &po.CreatedAt, &orderedAt, &po.TotalAmount)
+	if err != nil {
+		return nil, err
+	}
+	if expectedAt.Valid {
+		po.ExpectedAt = &expectedAt.Time
+	}
+	if orderedAt.Valid {
+		po.OrderedAt = &orderedAt.Time
+	}
+	return &po, nil
+}
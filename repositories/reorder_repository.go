@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type ReorderRepository struct {
+	db *sql.DB
+}
+
+func NewReorderRepository(db *sql.DB) *ReorderRepository {
+	return &ReorderRepository{db: db}
+}
+
+// GetStockVelocity - stok, qty terjual dalam windowDays terakhir (termasuk lewat bundle),
+// qty yang masih dipesan di PO terbuka dan supplier terakhir tiap item stok
+func (repo *ReorderRepository) GetStockVelocity(windowDays int, supplierID int) ([]models.ReorderSuggestion, error) {
+	query := `
+		WITH sales AS (
+			SELECT d.product_id, COALESCE(d.variant_id, 0) AS variant_id, SUM(d.base_quantity) AS quantity
+			FROM transaction_details d
+			JOIN transactions t ON t.id = d.transaction_id
+			WHERE t.created_at >= NOW() - make_interval(days => $1)
+				AND NOT EXISTS (SELECT 1 FROM transaction_detail_components c WHERE c.transaction_detail_id = d.id)
+			GROUP BY 1, 2
+			UNION ALL
+			SELECT c.product_id, 0, SUM(c.quantity)
+			FROM transaction_detail_components c
+			JOIN transaction_details d ON d.id = c.transaction_detail_id
+			JOIN transactions t ON t.id = d.transaction_id
+			WHERE t.created_at >= NOW() - make_interval(days => $1)
+			GROUP BY 1
+		), velocity AS (
+			SELECT product_id, variant_id, SUM(quantity) AS quantity FROM sales GROUP BY 1, 2
+		), on_order AS (
+			SELECT i.product_id, COALESCE(i.variant_id, 0) AS variant_id, SUM((i.quantity - i.received_qty) * i.unit_factor) AS quantity
+			FROM purchase_order_items i
+			JOIN purchase_orders po ON po.id = i.purchase_order_id
+			WHERE po.status IN ('ordered', 'partially_received')
+			GROUP BY 1, 2
+		), last_supplier AS (
+			SELECT DISTINCT ON (i.product_id) i.product_id, po.supplier_id
+			FROM purchase_order_items i
+			JOIN purchase_orders po ON po.id = i.purchase_order_id
+			WHERE po.status <> 'cancelled'
+			ORDER BY i.product_id, po.created_at DESC
+		)
+		SELECT p.id, p.name, COALESCE(v.id, 0), COALESCE(v.name, ''), COALESCE(s.id, 0), COALESCE(s.name, ''), COALESCE(s.lead_time_days, 0),
+			COALESCE(v.stock, p.stock), COALESCE(o.quantity, 0), p.min_stock, p.reorder_qty, COALESCE(vel.quantity, 0), p.cost_price
+		FROM products p
+		LEFT JOIN product_variants v ON v.product_id = p.id
+		LEFT JOIN velocity vel ON vel.product_id = p.id AND vel.variant_id = COALESCE(v.id, 0)
+		LEFT JOIN on_order o ON o.product_id = p.id AND o.variant_id = COALESCE(v.id, 0)
+		LEFT JOIN last_supplier ls ON ls.product_id = p.id
+		LEFT JOIN suppliers s ON s.id = ls.supplier_id
+		WHERE NOT ` + productIsBundleColumn + ` AND p.archived_at IS NULL AND ($2::int = 0 OR s.id = $2)
+		ORDER BY p.name, v.name
+	`
+
+	rows, err := repo.db.Query(query, windowDays, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ReorderSuggestion, 0)
+	for rows.Next() {
+		var r models.ReorderSuggestion
+		err := rows.Scan(&r.ProductID, &r.ProductName, &r.VariantID, &r.VariantName, &r.SupplierID, &r.SupplierName, &r.LeadTimeDays,
+			&r.Stock, &r.OnOrder, &r.MinStock, &r.ReorderQty, &r.QtySold, &r.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, r)
+	}
+
+	return items, nil
+}
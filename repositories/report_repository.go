@@ -16,7 +16,7 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 func (repo *ReportRepository) GetReport() (*models.Today, error) {
 	query :=
 		`
-			select sum(p.subtotal) as revenue, count(id) as total_transactions
+			select coalesce(sum(p.subtotal), 0) as revenue, coalesce(sum(p.unit_cost * p.quantity), 0) as cost, count(id) as total_transactions
 			from transaction_details p
 		`
 
@@ -27,21 +27,23 @@ func (repo *ReportRepository) GetReport() (*models.Today, error) {
 	defer rows.Close()
 
 	totalRevenue := 0
+	totalCost := 0
 	totalTransactions := 0
 	for rows.Next() {
-		var revenue, transaction int
-		err := rows.Scan(&revenue, &transaction)
+		var revenue, cost, transaction int
+		err := rows.Scan(&revenue, &cost, &transaction)
 		if err != nil {
 			return nil, err
 		}
 		totalRevenue += revenue
+		totalCost += cost
 		totalTransactions += transaction
 	}
 
 	var BestSelling models.BestSelling
 	queryBestSelling :=
 		`
-			select max(p.quantity) as qty_sold, pd.name
+			select max(p.base_quantity) as qty_sold, pd.name
 			from transaction_details p
 			left join products pd on pd.id = p.product_id
 			group by pd.name
@@ -69,6 +71,9 @@ func (repo *ReportRepository) GetReport() (*models.Today, error) {
 
 	return &models.Today{
 		TotalRevenue:      totalRevenue,
+		TotalCost:         totalCost,
+		GrossProfit:       totalRevenue - totalCost,
+		Margin:            grossMargin(totalRevenue, totalRevenue-totalCost),
 		TotalTransactions: totalTransactions,
 		BestSellingItem:   BestSelling,
 	}, nil
@@ -77,10 +82,11 @@ func (repo *ReportRepository) GetReport() (*models.Today, error) {
 func (repo *ReportRepository) GetReportDate(start_date string, end_date string) ([]models.ReportData, error) {
 	query :=
 		`
-			select p.id, t.created_at as datetime, pd.name, pd.price, p.quantity, p.subtotal, pd.stock
+			select p.id, t.created_at as datetime, pd.name, coalesce(v.name, ''), p.unit_name, coalesce(v.price, pd.price), p.quantity, p.subtotal, p.unit_cost, coalesce(v.stock, pd.stock)
 			from transaction_details p
 			left join transactions t on t.id = p.transaction_id
       		left join products pd on pd.id = p.product_id
+			left join product_variants v on v.id = p.variant_id
 		`
 	args := []interface{}{}
 	if start_date != "" && end_date != "" {
@@ -97,12 +103,249 @@ func (repo *ReportRepository) GetReportDate(start_date string, end_date string)
 	datareport := make([]models.ReportData, 0)
 	for rows.Next() {
 		var p models.ReportData
-		err := rows.Scan(&p.ID, &p.DateTime, &p.ProductName, &p.ProductPrice, &p.Qty, &p.SubTotal, &p.RemainingStock)
+		err := rows.Scan(&p.ID, &p.DateTime, &p.ProductName, &p.VariantName, &p.UnitName, &p.ProductPrice, &p.Qty, &p.SubTotal, &p.UnitCost, &p.RemainingStock)
 		if err != nil {
 			return nil, err
 		}
+		p.Cost = p.UnitCost * p.Qty
+		p.GrossProfit = p.SubTotal - p.Cost
+		p.Margin = grossMargin(p.SubTotal, p.GrossProfit)
 		datareport = append(datareport, p)
 	}
 
 	return datareport, nil
 }
+
+// GetProductSales - total qty dan omzet per produk, rollup "variant" memecah per varian,
+// rollup "component" memecah bundle ke produk komponennya
+func (repo *ReportRepository) GetProductSales(start_date string, end_date string, rollup string) ([]models.ProductSales, error) {
+	if rollup == "component" {
+		return repo.getComponentMovement(start_date, end_date)
+	}
+
+	variantColumns := "0, ''"
+	groupBy := "pd.id, pd.name"
+	if rollup == "variant" {
+		variantColumns = "coalesce(v.id, 0), coalesce(v.name, '')"
+		groupBy += ", v.id, v.name"
+	}
+
+	query := `
+		select pd.id, pd.name, ` + variantColumns + `, sum(p.base_quantity), sum(p.subtotal), sum(p.unit_cost * p.quantity)
+		from transaction_details p
+		join transactions t on t.id = p.transaction_id
+		join products pd on pd.id = p.product_id
+		left join product_variants v on v.id = p.variant_id
+	`
+	args := []interface{}{}
+	if start_date != "" && end_date != "" {
+		query += " WHERE t.created_at >= $1 and t.created_at <= $2"
+		args = append(args, start_date, end_date)
+	}
+	query += " GROUP BY " + groupBy + " ORDER BY sum(p.base_quantity) DESC"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var p models.ProductSales
+		err := rows.Scan(&p.ProductID, &p.ProductName, &p.VariantID, &p.VariantName, &p.QtySold, &p.Revenue, &p.Cost)
+		if err != nil {
+			return nil, err
+		}
+		p.GrossProfit = p.Revenue - p.Cost
+		p.Margin = grossMargin(p.Revenue, p.GrossProfit)
+		sales = append(sales, p)
+	}
+
+	return sales, nil
+}
+
+// getComponentMovement - qty per produk termasuk yang keluar lewat bundle, omzet dan HPP hanya dari penjualan langsung
+func (repo *ReportRepository) getComponentMovement(start_date string, end_date string) ([]models.ProductSales, error) {
+	dateFilter := ""
+	args := []interface{}{}
+	if start_date != "" && end_date != "" {
+		dateFilter = " and t.created_at >= $1 and t.created_at <= $2"
+		args = append(args, start_date, end_date)
+	}
+
+	query := `
+		with moves as (
+			select p.product_id, p.base_quantity as direct_qty, 0 as bundle_qty, p.subtotal, p.unit_cost * p.quantity as cost
+			from transaction_details p
+			join transactions t on t.id = p.transaction_id
+			where not exists (select 1 from transaction_detail_components c where c.transaction_detail_id = p.id)` + dateFilter + `
+			union all
+			select c.product_id, 0, c.quantity, 0, 0
+			from transaction_detail_components c
+			join transaction_details p on p.id = c.transaction_detail_id
+			join transactions t on t.id = p.transaction_id
+			where true` + dateFilter + `
+		)
+		select pd.id, pd.name, sum(m.direct_qty), sum(m.subtotal), sum(m.cost), sum(m.bundle_qty)
+		from moves m
+		join products pd on pd.id = m.product_id
+		group by pd.id, pd.name
+		order by sum(m.direct_qty + m.bundle_qty) desc
+	`
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var p models.ProductSales
+		err := rows.Scan(&p.ProductID, &p.ProductName, &p.QtySold, &p.Revenue, &p.Cost, &p.QtyViaBundles)
+		if err != nil {
+			return nil, err
+		}
+		p.GrossProfit = p.Revenue - p.Cost
+		p.Margin = grossMargin(p.Revenue, p.GrossProfit)
+		sales = append(sales, p)
+	}
+
+	return sales, nil
+}
+
+// GetCategorySales - penjualan di-roll up ke satu level pohon kategori, pakai kategori produk saat ini.
+// level > 0: tiap produk dihitung ke leluhurnya di kedalaman level (1 = kategori utama),
+// selain itu: rincian per sub kategori langsung dari parentID (0 = kategori utama), penjualan produk
+// yang langsung berada di parentID masuk ke baris parentID sendiri
+func (repo *ReportRepository) GetCategorySales(start_date string, end_date string, parentID int, level int) ([]models.CategorySales, error) {
+	dateFilter := ""
+	args := []interface{}{parentID, level}
+	if start_date != "" && end_date != "" {
+		dateFilter = " and t.created_at >= $3 and t.created_at <= $4"
+		args = append(args, start_date, end_date)
+	}
+
+	query := `
+		with recursive tree as (
+			select id, array[id] as ids, name::text as path from categories where parent_id is null
+			union all
+			select c.id, tree.ids || c.id, tree.path || ' > ' || c.name
+			from categories c
+			join tree on c.parent_id = tree.id
+		), sales as (
+			select tree.ids, p.product_id, p.base_quantity, p.subtotal, p.unit_cost * p.quantity as cost
+			from transaction_details p
+			join transactions t on t.id = p.transaction_id
+			join products pd on pd.id = p.product_id
+			left join tree on tree.id = pd.category_id
+			where true` + dateFilter + `
+		), grouped as (
+			select coalesce(case
+					when $2::int > 0 then ids[least($2::int, array_length(ids, 1))]
+					else ids[least(coalesce(array_position(ids, $1::int), 0) + 1, array_length(ids, 1))]
+				end, 0) as category_id,
+				count(distinct product_id) as products, sum(base_quantity) as qty, sum(subtotal) as revenue, sum(cost) as cost
+			from sales
+			where $2::int > 0 or $1::int = 0 or $1::int = any(ids)
+			group by 1
+		)
+		select g.category_id, coalesce(c.name, 'Uncategorised'), coalesce(tree.path, ''), g.products, g.qty, g.revenue, g.cost
+		from grouped g
+		left join categories c on c.id = g.category_id
+		left join tree on tree.id = g.category_id
+		order by g.revenue desc
+	`
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.CategorySales, 0)
+	for rows.Next() {
+		var c models.CategorySales
+		err := rows.Scan(&c.CategoryID, &c.CategoryName, &c.Path, &c.Products, &c.QtySold, &c.Revenue, &c.Cost)
+		if err != nil {
+			return nil, err
+		}
+		c.GrossProfit = c.Revenue - c.Cost
+		c.Margin = grossMargin(c.Revenue, c.GrossProfit)
+		sales = append(sales, c)
+	}
+
+	return sales, nil
+}
+
+// GetModifierSales - berapa kali tiap modifier dipilih dan tambahan omzetnya
+func (repo *ReportRepository) GetModifierSales(start_date string, end_date string) ([]models.ModifierSales, error) {
+	query := `
+		select coalesce(m.modifier_id, 0), m.name, coalesce(g.name, ''), sum(p.quantity), sum(m.price_delta * p.quantity)
+		from transaction_detail_modifiers m
+		join transaction_details p on p.id = m.transaction_detail_id
+		join transactions t on t.id = p.transaction_id
+		left join modifiers md on md.id = m.modifier_id
+		left join modifier_groups g on g.id = md.group_id
+	`
+	args := []interface{}{}
+	if start_date != "" && end_date != "" {
+		query += " WHERE t.created_at >= $1 and t.created_at <= $2"
+		args = append(args, start_date, end_date)
+	}
+	query += " GROUP BY m.modifier_id, m.name, g.name ORDER BY sum(p.quantity) DESC"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]models.ModifierSales, 0)
+	for rows.Next() {
+		var m models.ModifierSales
+		err := rows.Scan(&m.ModifierID, &m.Name, &m.GroupName, &m.QtySold, &m.Revenue)
+		if err != nil {
+			return nil, err
+		}
+		sales = append(sales, m)
+	}
+
+	return sales, nil
+}
+
+// GetTransactionProfits - omzet, HPP dan laba kotor per transaksi
+func (repo *ReportRepository) GetTransactionProfits(start_date string, end_date string) ([]models.Transaction, error) {
+	query := `
+		select t.id, t.total_amount, coalesce(sum(p.unit_cost * p.quantity), 0), coalesce(t.order_status, ''), t.created_at
+		from transactions t
+		left join transaction_details p on p.transaction_id = t.id
+	`
+	args := []interface{}{}
+	if start_date != "" && end_date != "" {
+		query += " WHERE t.created_at >= $1 and t.created_at <= $2"
+		args = append(args, start_date, end_date)
+	}
+	query += " GROUP BY t.id ORDER BY t.created_at DESC, t.id DESC"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0)
+	for rows.Next() {
+		var t models.Transaction
+		err := rows.Scan(&t.ID, &t.TotalAmount, &t.TotalCost, &t.OrderStatus, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		t.GrossProfit = t.TotalAmount - t.TotalCost
+		t.Margin = grossMargin(t.TotalAmount, t.GrossProfit)
+		transactions = append(transactions, t)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type StockAlertRepository struct {
+	db *sql.DB
+}
+
+func NewStockAlertRepository(db *sql.DB) *StockAlertRepository {
+	return &StockAlertRepository{db: db}
+}
+
+// GetLowStock - produk yang stoknya sudah di titik pesan ulang, bundle tidak ikut karena stoknya dari komponen
+func (repo *StockAlertRepository) GetLowStock() ([]models.LowStockItem, error) {
+	rows, err := repo.db.Query(`
+		SELECT p.id, p.name, COALESCE(c.name, ''), p.stock, p.min_stock, p.reorder_qty
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.min_stock > 0 AND p.stock <= p.min_stock AND p.archived_at IS NULL AND NOT ` + productIsBundleColumn + `
+		ORDER BY p.stock - p.min_stock, p.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.LowStockItem, 0)
+	for rows.Next() {
+		var item models.LowStockItem
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.CategoryName, &item.Stock, &item.MinStock, &item.ReorderQty)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (repo *StockAlertRepository) GetAll(limit int) ([]models.StockAlert, error) {
+	rows, err := repo.db.Query(`
+		SELECT a.id, a.product_id, p.name, a.stock, a.min_stock, a.reorder_qty, COALESCE(a.transaction_id, 0), a.created_at
+		FROM stock_alerts a
+		JOIN products p ON p.id = a.product_id
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStockAlerts(rows)
+}
+
+// CreateForTransaction - alert untuk produk yang turun melewati min_stock karena transaksi ini,
+// produk yang sudah di bawah min_stock sebelumnya tidak dialert ulang
+func (repo *StockAlertRepository) CreateForTransaction(transactionID int) ([]models.StockAlert, error) {
+	rows, err := repo.db.Query(`
+		WITH sold AS (
+			SELECT d.product_id, SUM(d.base_quantity) AS quantity
+			FROM transaction_details d
+			WHERE d.transaction_id = $1
+				AND NOT EXISTS (SELECT 1 FROM transaction_detail_components c WHERE c.transaction_detail_id = d.id)
+			GROUP BY d.product_id
+			UNION ALL
+			SELECT c.product_id, SUM(c.quantity)
+			FROM transaction_detail_components c
+			JOIN transaction_details d ON d.id = c.transaction_detail_id
+			WHERE d.transaction_id = $1
+			GROUP BY c.product_id
+		), inserted AS (
+			INSERT INTO stock_alerts (product_id, stock, min_stock, reorder_qty, transaction_id)
+			SELECT p.id, p.stock, p.min_stock, p.reorder_qty, $1
+			FROM (SELECT product_id, SUM(quantity) AS quantity FROM sold GROUP BY product_id) s
+			JOIN products p ON p.id = s.product_id
+			WHERE p.min_stock > 0 AND p.stock <= p.min_stock AND p.stock + s.quantity > p.min_stock
+			RETURNING id, product_id, stock, min_stock, reorder_qty, transaction_id, created_at
+		)
+		SELECT i.id, i.product_id, p.name, i.stock, i.min_stock, i.reorder_qty, i.transaction_id, i.created_at
+		FROM inserted i
+		JOIN products p ON p.id = i.product_id
+		ORDER BY i.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanStockAlerts(rows)
+}
+
+func scanStockAlerts(rows *sql.Rows) ([]models.StockAlert, error) {
+	alerts := make([]models.StockAlert, 0)
+	for rows.Next() {
+		var a models.StockAlert
+		err := rows.Scan(&a.ID, &a.ProductID, &a.ProductName, &a.Stock, &a.MinStock, &a.ReorderQty, &a.TransactionID, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
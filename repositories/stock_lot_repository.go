@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+)
+
+type StockLotRepository struct {
+	db *sql.DB
+}
+
+func NewStockLotRepository(db *sql.DB) *StockLotRepository {
+	return &StockLotRepository{db: db}
+}
+
+const stockLotColumns = `
+	SELECT l.id, l.product_id, p.name, COALESCE(l.variant_id, 0), COALESCE(v.name, ''), COALESCE(l.goods_receipt_id, 0), l.lot_code,
+		l.received_at, l.expiry_date, l.expiry_date - CURRENT_DATE, l.unit_cost, l.quantity_received, l.quantity_remaining
+	FROM stock_lots l
+	JOIN products p ON p.id = l.product_id
+	LEFT JOIN product_variants v ON v.id = l.variant_id
+`
+
+// GetByProduct - lot produk, includeEmpty = termasuk lot yang sudah habis
+func (repo *StockLotRepository) GetByProduct(productID int, includeEmpty bool) ([]models.StockLot, error) {
+	query := stockLotColumns + " WHERE l.product_id = $1"
+	if !includeEmpty {
+		query += " AND l.quantity_remaining > 0"
+	}
+	query += " ORDER BY l.received_at, l.id"
+
+	return repo.queryLots(query, productID)
+}
+
+func (repo *StockLotRepository) GetByID(id int) (*models.StockLot, error) {
+	lots, err := repo.queryLots(stockLotColumns+" WHERE l.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) == 0 {
+		return nil, errors.New("Stock lot not found")
+	}
+	return &lots[0], nil
+}
+
+// GetExpiring - lot yang masih ada sisa dan kedaluwarsa dalam N hari, termasuk yang sudah lewat
+func (repo *StockLotRepository) GetExpiring(days int) ([]models.StockLot, error) {
+	query := stockLotColumns + `
+		WHERE l.quantity_remaining > 0 AND l.expiry_date <= CURRENT_DATE + $1::int
+		ORDER BY l.expiry_date, p.name, l.id
+	`
+	return repo.queryLots(query, days)
+}
+
+// Create - pindahkan stok tanpa lot ke lot baru, jumlah stok tidak berubah
+func (repo *StockLotRepository) Create(productID int, req models.StockLotRequest) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := checkStockItem(tx, productID, req.VariantID); err != nil {
+		return 0, err
+	}
+
+	var stock, lotted int
+	err = tx.QueryRow("SELECT stock FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&stock)
+	if err != nil {
+		return 0, err
+	}
+	if req.VariantID > 0 {
+		err = tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1", req.VariantID).Scan(&stock)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = tx.QueryRow("SELECT COALESCE(SUM(quantity_remaining), 0) FROM stock_lots WHERE product_id = $1 AND COALESCE(variant_id, 0) = $2", productID, req.VariantID).Scan(&lotted)
+	if err != nil {
+		return 0, err
+	}
+	if req.Quantity > stock-lotted {
+		return 0, fmt.Errorf("only %d units of stock are not assigned to a lot", max(stock-lotted, 0))
+	}
+
+	id, err := createLot(tx, productID, req.VariantID, 0, req.LotCode, req.ExpiryDate, req.UnitCost, req.Quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (repo *StockLotRepository) queryLots(query string, args ...interface{}) ([]models.StockLot, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := make([]models.StockLot, 0)
+	for rows.Next() {
+		var l models.StockLot
+		var expiryDate sql.NullTime
+		var daysLeft sql.NullInt64
+		err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.VariantID, &l.VariantName, &l.GoodsReceiptID, &l.LotCode,
+			&l.ReceivedAt, &expiryDate, &daysLeft, &l.UnitCost, &l.QuantityReceived, &l.QuantityRemaining)
+		if err != nil {
+			return nil, err
+		}
+		if expiryDate.Valid {
+			l.ExpiryDate = &expiryDate.Time
+			days := int(daysLeft.Int64)
+			l.DaysLeft = &days
+			l.Expired = days < 0
+		}
+		l.RemainingValue = l.QuantityRemaining * l.UnitCost
+		lots = append(lots, l)
+	}
+
+	return lots, nil
+}
+
+// createLot - unit_cost 0 = harga modal produk saat ini
+func createLot(tx *sql.Tx, productID int, variantID int, goodsReceiptID int, lotCode string, expiryDate string, unitCost int, quantity int) (int, error) {
+	var id int
+	err := tx.QueryRow(`
+		INSERT INTO stock_lots (product_id, variant_id, goods_receipt_id, lot_code, expiry_date, unit_cost, quantity_received, quantity_remaining)
+		VALUES ($1, NULLIF($2::int, 0), NULLIF($3::int, 0), $4, NULLIF($5, '')::date,
+			COALESCE(NULLIF($6::int, 0), (SELECT cost_price FROM products WHERE id = $1)), $7, $7)
+		RETURNING id
+	`, productID, variantID, goodsReceiptID, lotCode, expiryDate, unitCost, quantity).Scan(&id)
+	return id, err
+}
+
+// checkExpiredLots - stok layak jual = stok tanpa lot + lot yang belum kedaluwarsa.
+// Kalau kurang, checkout ditolak atau diberi peringatan sesuai allow_expired_sale produk
+func checkExpiredLots(tx *sql.Tx, productID int, variantID int, quantity int) (string, error) {
+	var name string
+	var allowExpired bool
+	var stock, lotted, expired int
+	err := tx.QueryRow(`
+		SELECT p.name, p.allow_expired_sale,
+			CASE WHEN $2::int > 0 THEN (SELECT stock FROM product_variants WHERE id = $2) ELSE p.stock END,
+			COALESCE(SUM(l.quantity_remaining), 0),
+			COALESCE(SUM(l.quantity_remaining) FILTER (WHERE l.expiry_date < CURRENT_DATE), 0)
+		FROM products p
+		LEFT JOIN stock_lots l ON l.product_id = p.id AND COALESCE(l.variant_id, 0) = $2 AND l.quantity_remaining > 0
+		WHERE p.id = $1
+		GROUP BY p.id
+	`, productID, variantID).Scan(&name, &allowExpired, &stock, &lotted, &expired)
+	if err != nil {
+		return "", err
+	}
+	if expired == 0 {
+		return "", nil
+	}
+
+	usable := max(stock-lotted, 0) + lotted - expired
+	if quantity <= usable {
+		return "", nil
+	}
+	if !allowExpired {
+		return "", models.NewValidationError("product %s only has %d unexpired units in stock", name, usable)
+	}
+	return fmt.Sprintf("%d unit(s) of %s taken from expired lots", min(quantity-usable, expired), name), nil
+}
+
+// consumeLots - kurangi sisa lot untuk mutasi keluar, dipanggil setelah stok berubah.
+// Stok tanpa lot habis duluan, lalu lot sesuai lot_policy (fifo/fefo).
+// Penjualan mendahulukan lot yang belum kedaluwarsa
+func consumeLots(tx *sql.Tx, movement *models.StockMovement) error {
+	var policy string
+	var stock, lotted int
+	err := tx.QueryRow(`
+		SELECT p.lot_policy,
+			CASE WHEN $2::int > 0 THEN (SELECT stock FROM product_variants WHERE id = $2) ELSE p.stock END,
+			COALESCE((SELECT SUM(quantity_remaining) FROM stock_lots WHERE product_id = $1 AND COALESCE(variant_id, 0) = $2), 0)
+		FROM products p
+		WHERE p.id = $1
+	`, movement.ProductID, movement.VariantID).Scan(&policy, &stock, &lotted)
+	if err != nil {
+		return err
+	}
+	if lotted == 0 {
+		return nil
+	}
+
+	unlotted := max(stock-movement.QuantityDelta-lotted, 0)
+	need := -movement.QuantityDelta - unlotted
+	if need <= 0 {
+		return nil
+	}
+
+	order := "received_at, id"
+	if policy == models.LotPolicyFEFO {
+		order = "expiry_date NULLS LAST, received_at, id"
+	}
+	if movement.Reason == models.MovementSale {
+		order = "COALESCE(expiry_date < CURRENT_DATE, false), " + order
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, quantity_remaining
+		FROM stock_lots
+		WHERE product_id = $1 AND COALESCE(variant_id, 0) = $2 AND quantity_remaining > 0
+		ORDER BY `+order+`
+		FOR UPDATE
+	`, movement.ProductID, movement.VariantID)
+	if err != nil {
+		return err
+	}
+	type lot struct{ id, remaining int }
+	lots := make([]lot, 0)
+	for rows.Next() {
+		var l lot
+		if err := rows.Scan(&l.id, &l.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		lots = append(lots, l)
+	}
+	rows.Close()
+
+	for _, l := range lots {
+		if need == 0 {
+			break
+		}
+		take := min(need, l.remaining)
+		if _, err := tx.Exec("UPDATE stock_lots SET quantity_remaining = quantity_remaining - $1 WHERE id = $2", take, l.id); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT INTO stock_lot_consumptions (lot_id, stock_movement_id, quantity) VALUES ($1, $2, $3)", l.id, movement.ID, take)
+		if err != nil {
+			return err
+		}
+		need -= take
+	}
+
+	return nil
+}
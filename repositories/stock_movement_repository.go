@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+	"strconv"
+)
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// GetByProduct - histori mutasi stok produk, terbaru dulu
+func (repo *StockMovementRepository) GetByProduct(productID int, start_date string, end_date string, limit int) ([]models.StockMovement, error) {
+	query := `
+		SELECT id, product_id, COALESCE(variant_id, 0), quantity_delta, balance_after, reason,
+			COALESCE(reference_type, ''), COALESCE(reference_id, 0), COALESCE(user_name, ''), COALESCE(note, ''), created_at
+		FROM stock_movements
+		WHERE product_id = $1
+	`
+	args := []interface{}{productID}
+	if start_date != "" && end_date != "" {
+		query += " AND created_at >= $2 AND created_at <= $3"
+		args = append(args, start_date, end_date)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if limit > 0 {
+		args = append(args, limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.ProductID, &m.VariantID, &m.QuantityDelta, &m.BalanceAfter, &m.Reason,
+			&m.ReferenceType, &m.ReferenceID, &m.User, &m.Note, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, nil
+}
+
+// Adjust - koreksi stok manual beserta catatan ledger-nya
+func (repo *StockMovementRepository) Adjust(movement *models.StockMovement) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := changeStock(tx, movement); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// changeStock - ubah stok sebesar QuantityDelta lalu catat ke ledger
+func changeStock(tx *sql.Tx, movement *models.StockMovement) error {
+	if movement.VariantID > 0 {
+		result, err := tx.Exec("UPDATE product_variants SET stock = stock + $1 WHERE id = $2 AND product_id = $3", movement.QuantityDelta, movement.VariantID, movement.ProductID)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return errors.New("Variant not found")
+		}
+	}
+
+	result, err := tx.Exec("UPDATE products SET stock = stock + $1 WHERE id = $2", movement.QuantityDelta, movement.ProductID)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New("Product not found")
+	}
+
+	return recordMovement(tx, movement)
+}
+
+// recordMovement - tulis ledger setelah stok berubah, balance_after diambil dari stok terkini.
+// Mutasi keluar juga mengurangi sisa lot
+func recordMovement(tx *sql.Tx, movement *models.StockMovement) error {
+	if movement.QuantityDelta == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO stock_movements (product_id, variant_id, quantity_delta, balance_after, reason, reference_type, reference_id, user_name, note)
+		VALUES ($1, NULLIF($2::int, 0), $3,
+			CASE WHEN $2::int > 0
+				THEN (SELECT stock FROM product_variants WHERE id = $2)
+				ELSE (SELECT stock FROM products WHERE id = $1)
+			END,
+			$4, NULLIF($5, ''), NULLIF($6::int, 0), NULLIF($7, ''), NULLIF($8, ''))
+		RETURNING id, balance_after, created_at
+	`
+	err := tx.QueryRow(query, movement.ProductID, movement.VariantID, movement.QuantityDelta, movement.Reason,
+		movement.ReferenceType, movement.ReferenceID, movement.User, movement.Note).Scan(&movement.ID, &movement.BalanceAfter, &movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if movement.QuantityDelta < 0 {
+		return consumeLots(tx, movement)
+	}
+	return nil
+}
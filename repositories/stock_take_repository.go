@@ -0,0 +1,297 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+)
+
+type StockTakeRepository struct {
+	db *sql.DB
+}
+
+func NewStockTakeRepository(db *sql.DB) *StockTakeRepository {
+	return &StockTakeRepository{db: db}
+}
+
+// Start - buka sesi opname dan snapshot stok saat ini, per varian untuk produk bervarian.
+// Bundle dilewati karena stoknya mengikuti komponen
+func (repo *StockTakeRepository) Start(st *models.StockTake) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("INSERT INTO stock_takes (category_id, note, created_by) VALUES (NULLIF($1::int, 0), $2, $3) RETURNING id, status, started_at",
+		st.CategoryID, st.Note, st.CreatedBy).Scan(&st.ID, &st.Status, &st.StartedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO stock_take_lines (stock_take_id, product_id, variant_id, expected_qty)
+		SELECT $1, p.id, v.id, COALESCE(v.stock, p.stock)
+		FROM products p
+		LEFT JOIN product_variants v ON v.product_id = p.id
+		WHERE NOT `+productIsBundleColumn+`
+			AND p.archived_at IS NULL
+			AND ($2::int = 0 OR p.category_id IN `+categorySubtree("$2")+`)
+	`, st.ID, st.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *StockTakeRepository) GetAll() ([]models.StockTake, error) {
+	rows, err := repo.db.Query(`
+		SELECT id, COALESCE(category_id, 0), status, note, created_by, approved_by, started_at, approved_at
+		FROM stock_takes
+		ORDER BY started_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stockTakes := make([]models.StockTake, 0)
+	for rows.Next() {
+		st, err := scanStockTake(rows)
+		if err != nil {
+			return nil, err
+		}
+		stockTakes = append(stockTakes, *st)
+	}
+
+	return stockTakes, nil
+}
+
+// GetByID - sesi beserta semua baris dan selisihnya
+func (repo *StockTakeRepository) GetByID(id int) (*models.StockTake, error) {
+	row := repo.db.QueryRow(`
+		SELECT id, COALESCE(category_id, 0), status, note, created_by, approved_by, started_at, approved_at
+		FROM stock_takes
+		WHERE id = $1
+	`, id)
+	st, err := scanStockTake(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Stock take not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st.Lines, err = stockTakeLines(repo.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// SubmitCounts - simpan hasil hitung satu batch scanner, seluruh batch gagal kalau ada item yang tidak dikenal
+func (repo *StockTakeRepository) SubmitCounts(id int, counts []models.StockTakeCount) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		productID, variantID, factor, err := resolveCountItem(tx, c)
+		if err != nil {
+			return err
+		}
+
+		query := "UPDATE stock_take_lines SET counted_qty = $1 WHERE stock_take_id = $2 AND product_id = $3 AND COALESCE(variant_id, 0) = $4"
+		if c.Mode == "add" {
+			query = "UPDATE stock_take_lines SET counted_qty = COALESCE(counted_qty, 0) + $1 WHERE stock_take_id = $2 AND product_id = $3 AND COALESCE(variant_id, 0) = $4"
+		}
+		result, err := tx.Exec(query, c.Quantity*factor, id, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			if variantID > 0 {
+				return fmt.Errorf("variant id %d of product id %d is not part of this stock take", variantID, productID)
+			}
+			return fmt.Errorf("product id %d is not part of this stock take", productID)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Approve - posting selisih ke stok sebagai mutasi stock_take.
+// Selisih = counted - expected dan ditambahkan ke stok terkini, jadi penjualan selama opname tetap terhitung
+func (repo *StockTakeRepository) Approve(id int, zeroUncounted bool, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	lines, err := stockTakeLines(tx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range lines {
+		counted := 0
+		if l.Counted != nil {
+			counted = *l.Counted
+		} else if !zeroUncounted {
+			continue
+		}
+
+		err := changeStock(tx, &models.StockMovement{
+			ProductID:     l.ProductID,
+			VariantID:     l.VariantID,
+			QuantityDelta: counted - l.Expected,
+			Reason:        models.MovementStockTake,
+			ReferenceType: "stock_take",
+			ReferenceID:   id,
+			User:          user,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, approved_by = $2, approved_at = NOW() WHERE id = $3", models.StockTakeApproved, user, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *StockTakeRepository) Cancel(id int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1 WHERE id = $2", models.StockTakeCancelled, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockOpenStockTake(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("Stock take id %d not found", id)
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockTakeOpen {
+		return fmt.Errorf("Stock take id %d is %s", id, status)
+	}
+	return nil
+}
+
+// resolveCountItem - cari product/variant dari id, SKU varian atau barcode produk/satuan,
+// beserta isi satuan untuk konversi ke satuan dasar
+func resolveCountItem(tx *sql.Tx, c models.StockTakeCount) (int, int, int, error) {
+	if c.Quantity < 0 {
+		return 0, 0, 0, errors.New("quantity cannot be negative")
+	}
+
+	switch {
+	case c.SKU != "":
+		var productID, variantID int
+		err := tx.QueryRow("SELECT product_id, id FROM product_variants WHERE sku = $1", c.SKU).Scan(&productID, &variantID)
+		if err == sql.ErrNoRows {
+			return 0, 0, 0, fmt.Errorf("sku %s not found", c.SKU)
+		}
+		return productID, variantID, 1, err
+	case c.Barcode != "":
+		var productID, factor int
+		err := tx.QueryRow(`
+			SELECT id, 1 FROM products WHERE barcode = $1
+			UNION ALL
+			SELECT product_id, factor FROM product_units WHERE barcode = $1
+			LIMIT 1
+		`, c.Barcode).Scan(&productID, &factor)
+		if err == sql.ErrNoRows {
+			return 0, 0, 0, fmt.Errorf("barcode %s not found", c.Barcode)
+		}
+		return productID, c.VariantID, factor, err
+	case c.ProductID > 0:
+		_, factor, _, err := productUnit(tx, c.ProductID, c.UnitID)
+		return c.ProductID, c.VariantID, factor, err
+	}
+
+	return 0, 0, 0, errors.New("product_id, sku or barcode is required")
+}
+
+// stockTakeLines - baris opname dengan nilai selisih berdasarkan harga modal (rata-rata tertimbang) produk,
+// varian tidak punya harga modal sendiri
+func stockTakeLines(q queryer, id int) ([]models.StockTakeLine, error) {
+	rows, err := q.Query(`
+		SELECT l.id, l.product_id, p.name, COALESCE(l.variant_id, 0), COALESCE(v.name, ''), l.expected_qty, l.counted_qty,
+			p.cost_price
+		FROM stock_take_lines l
+		JOIN products p ON p.id = l.product_id
+		LEFT JOIN product_variants v ON v.id = l.variant_id
+		WHERE l.stock_take_id = $1
+		ORDER BY p.name, v.name, l.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]models.StockTakeLine, 0)
+	for rows.Next() {
+		var l models.StockTakeLine
+		var counted sql.NullInt64
+		err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.VariantID, &l.VariantName, &l.Expected, &counted, &l.UnitValue)
+		if err != nil {
+			return nil, err
+		}
+		if counted.Valid {
+			n := int(counted.Int64)
+			l.Counted = &n
+			l.Variance = n - l.Expected
+			l.VarianceValue = l.Variance * l.UnitValue
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, nil
+}
+
+func scanStockTake(row rowScanner) (*models.StockTake, error) {
+	var st models.StockTake
+	var approvedAt sql.NullTime
+	err := row.Scan(&st.ID, &st.CategoryID, &st.Status, &st.Note, &st.CreatedBy, &st.ApprovedBy, &st.StartedAt, &approvedAt)
+	if err != nil {
+		return nil, err
+	}
+	if approvedAt.Valid {
+		st.ApprovedAt = &approvedAt.Time
+	}
+	return &st, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+)
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+func (repo *SupplierRepository) GetAll() ([]models.Supplier, error) {
+	rows, err := repo.db.Query("SELECT id, name, contact_name, phone, email, address, lead_time_days FROM suppliers ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		var s models.Supplier
+		err := rows.Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.LeadTimeDays)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	return suppliers, nil
+}
+
+func (repo *SupplierRepository) Create(supplier *models.Supplier) error {
+	query := "INSERT INTO suppliers (name, contact_name, phone, email, address, lead_time_days) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+	return repo.db.QueryRow(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.LeadTimeDays).Scan(&supplier.ID)
+}
+
+func (repo *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+	query := "SELECT id, name, contact_name, phone, email, address, lead_time_days FROM suppliers WHERE id = $1"
+
+	var s models.Supplier
+	err := repo.db.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.LeadTimeDays)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Supplier not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (repo *SupplierRepository) Update(supplier *models.Supplier) error {
+	query := "UPDATE suppliers SET name = $1, contact_name = $2, phone = $3, email = $4, address = $5, lead_time_days = $6 WHERE id = $7"
+	result, err := repo.db.Exec(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.LeadTimeDays, supplier.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Supplier not found")
+	}
+
+	return nil
+}
+
+// Delete - supplier yang sudah punya PO tidak bisa dihapus
+func (repo *SupplierRepository) Delete(id int) error {
+	query := "DELETE FROM suppliers WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM purchase_orders WHERE supplier_id = $1)"
+	result, err := repo.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Supplier not found or still has purchase orders")
+	}
+
+	return nil
+}
@@ -0,0 +1,408 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type TabRepository struct {
+	db *sql.DB
+}
+
+func NewTabRepository(db *sql.DB) *TabRepository {
+	return &TabRepository{db: db}
+}
+
+const tabColumns = "t.id, COALESCE(t.table_id, 0), COALESCE(d.name, ''), t.status, COALESCE(t.merged_into, 0), t.opened_at, t.closed_at"
+
+func (repo *TabRepository) GetOpen() ([]models.Tab, error) {
+	query := "SELECT " + tabColumns + " FROM tabs t LEFT JOIN dining_tables d ON d.id = t.table_id WHERE t.status = 'open' ORDER BY t.opened_at"
+	rows, err := repo.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	tabs := make([]models.Tab, 0)
+	for rows.Next() {
+		t, err := scanTab(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tabs = append(tabs, *t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range tabs {
+		if err := loadTabItems(repo.db, &tabs[i]); err != nil {
+			return nil, err
+		}
+		tabs[i].Items = nil
+	}
+
+	return tabs, nil
+}
+
+func (repo *TabRepository) GetByID(id int) (*models.Tab, error) {
+	query := "SELECT " + tabColumns + " FROM tabs t LEFT JOIN dining_tables d ON d.id = t.table_id WHERE t.id = $1"
+	tab, err := scanTab(repo.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Tab not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTabItems(repo.db, tab); err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+func (repo *TabRepository) Open(tableID int) (int, error) {
+	var id int
+	err := repo.db.QueryRow("INSERT INTO tabs (table_id) VALUES ($1) RETURNING id", tableID).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, errors.New("Table already has an open tab")
+	}
+	return id, err
+}
+
+// AddItems - tambah satu ronde pesanan, item divalidasi dengan aturan checkout yang sama.
+// Item dapur/bar di ronde ini jadi satu tiket dapur (status new), roundID 0 kalau tidak ada item dapur/bar
+func (repo *TabRepository) AddItems(tabID int, items []models.CheckoutItem) (int, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenTab(tx, tabID); err != nil {
+		return 0, err
+	}
+	if err := checkActiveProducts(tx, items); err != nil {
+		return 0, err
+	}
+
+	roundID := 0
+	for _, item := range items {
+		detail, err := resolveItem(tx, item)
+		if err != nil {
+			return 0, err
+		}
+		if detail.Station != "" && roundID == 0 {
+			err = tx.QueryRow("INSERT INTO tab_rounds (tab_id, order_status) VALUES ($1, $2) RETURNING id", tabID, models.OrderStatusNew).Scan(&roundID)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		round := 0
+		if detail.Station != "" {
+			round = roundID
+		}
+		_, err = tx.Exec("INSERT INTO tab_items (tab_id, product_id, variant_id, unit_id, quantity, modifiers, round_id) VALUES ($1, $2, NULLIF($3::int, 0), NULLIF($4::int, 0), $5, $6, NULLIF($7::int, 0))",
+			tabID, item.ProductID, item.VariantID, item.UnitID, item.Quantity, pq.Array(item.Modifiers), round)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return roundID, nil
+}
+
+// RemoveItem - hanya item yang belum dibayar
+func (repo *TabRepository) RemoveItem(tabID, itemID int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenTab(tx, tabID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM tab_items WHERE id = $1 AND tab_id = $2 AND transaction_id IS NULL", itemID, tabID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Tab item not found or already paid")
+	}
+
+	return tx.Commit()
+}
+
+func (repo *TabRepository) Transfer(tabID, tableID int) error {
+	result, err := repo.db.Exec("UPDATE tabs SET table_id = $1 WHERE id = $2 AND status = 'open'", tableID, tabID)
+	if isUniqueViolation(err) {
+		return errors.New("Target table already has an open tab, merge the tabs instead")
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Tab not found or not open")
+	}
+
+	return nil
+}
+
+// Merge - pindahkan item belum dibayar dari source ke target, source ditutup sebagai merged
+func (repo *TabRepository) Merge(targetID, sourceID int) error {
+	if targetID == sourceID {
+		return errors.New("Cannot merge a tab into itself")
+	}
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// kunci berurutan supaya tidak deadlock
+	first, second := targetID, sourceID
+	if first > second {
+		first, second = second, first
+	}
+	if err := lockOpenTab(tx, first); err != nil {
+		return err
+	}
+	if err := lockOpenTab(tx, second); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE tab_items SET tab_id = $1 WHERE tab_id = $2 AND transaction_id IS NULL", targetID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	// tiket dapur ikut pindah supaya display menampilkan meja tujuan
+	_, err = tx.Exec("UPDATE tab_rounds SET tab_id = $1 WHERE tab_id = $2", targetID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE tabs SET status = 'merged', merged_into = $1, closed_at = NOW() WHERE id = $2", targetID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Settle - bayar item tab lewat jalur checkout, itemIDs kosong = semua yang belum dibayar.
+// shares > 1 = total dibagi rata dan tiap bagian dicatat sebagai pembayaran transaksi.
+// Ronde tab (tiket dapur) dari item yang dibayar diisi ke settlement. Tab ditutup kalau tidak ada item tersisa
+func (repo *TabRepository) Settle(tabID int, itemIDs []int, shares int) (*models.TabSettlement, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenTab(tx, tabID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, product_id, COALESCE(variant_id, 0), COALESCE(unit_id, 0), quantity, modifiers, COALESCE(round_id, 0)
+		FROM tab_items
+		WHERE tab_id = $1 AND transaction_id IS NULL
+		  AND (cardinality($2::int[]) = 0 OR id = ANY($2))
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(query, tabID, pq.Array(itemIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	paidIDs := make([]int, 0)
+	items := make([]models.CheckoutItem, 0)
+	rounds := make([]int, 0)
+	seenRound := make(map[int]bool)
+	for rows.Next() {
+		var id, roundID int
+		var item models.CheckoutItem
+		var modifiers pq.Int64Array
+		if err := rows.Scan(&id, &item.ProductID, &item.VariantID, &item.UnitID, &item.Quantity, &modifiers, &roundID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		item.Modifiers = toInts(modifiers)
+		paidIDs = append(paidIDs, id)
+		items = append(items, item)
+		if roundID > 0 && !seenRound[roundID] {
+			seenRound[roundID] = true
+			rounds = append(rounds, roundID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("No unpaid items to settle")
+	}
+	if len(itemIDs) > 0 && len(paidIDs) != len(itemIDs) {
+		return nil, errors.New("Some items are not in this tab or already paid")
+	}
+
+	// item dapur/bar sudah punya tiket dari ronde masing-masing, transaksinya tidak masuk antrian lagi
+	transaction, err := createTransaction(tx, items, false)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("UPDATE tab_items SET transaction_id = $1 WHERE id = ANY($2)", transaction.ID, pq.Array(paidIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &models.TabSettlement{Transaction: transaction, TabRounds: rounds, TabStatus: models.TabStatusOpen}
+	if shares > 1 {
+		if shares > transaction.TotalAmount {
+			return nil, errors.New("Shares cannot be more than the total amount")
+		}
+		for i, amount := range splitEqually(transaction.TotalAmount, shares) {
+			payment := models.TransactionPayment{TransactionID: transaction.ID, Share: i + 1, Amount: amount}
+			err = tx.QueryRow("INSERT INTO transaction_payments (transaction_id, share, amount) VALUES ($1, $2, $3) RETURNING id, created_at",
+				payment.TransactionID, payment.Share, payment.Amount).Scan(&payment.ID, &payment.CreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			settlement.Payments = append(settlement.Payments, payment)
+		}
+	}
+
+	var remaining bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM tab_items WHERE tab_id = $1 AND transaction_id IS NULL)", tabID).Scan(&remaining)
+	if err != nil {
+		return nil, err
+	}
+	if !remaining {
+		settlement.TabStatus = models.TabStatusClosed
+		_, err = tx.Exec("UPDATE tabs SET status = 'closed', closed_at = NOW() WHERE id = $1", tabID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// splitEqually - sisa pembagian dibebankan ke share pertama, satu rupiah per share
+func splitEqually(total, shares int) []int {
+	result := make([]int, shares)
+	base := total / shares
+	remainder := total % shares
+	for i := range result {
+		result[i] = base
+		if i < remainder {
+			result[i]++
+		}
+	}
+	return result
+}
+
+func lockOpenTab(tx *sql.Tx, tabID int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM tabs WHERE id = $1 FOR UPDATE", tabID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("Tab id %d not found", tabID)
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.TabStatusOpen {
+		return fmt.Errorf("Tab id %d is %s", tabID, status)
+	}
+	return nil
+}
+
+func scanTab(row rowScanner) (*models.Tab, error) {
+	var t models.Tab
+	var closedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.TableID, &t.TableName, &t.Status, &t.MergedInto, &t.OpenedAt, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	if closedAt.Valid {
+		t.ClosedAt = &closedAt.Time
+	}
+	return &t, nil
+}
+
+// loadTabItems - isi item dan total tab, harga dihitung dari harga produk/varian, satuan dan modifier saat ini
+func loadTabItems(q queryer, tab *models.Tab) error {
+	query := `
+		SELECT i.id, i.product_id, p.name, COALESCE(i.variant_id, 0), COALESCE(v.name, ''), COALESCE(i.unit_id, 0), COALESCE(u.name, ''), i.quantity, i.modifiers,
+			(COALESCE(u.price, COALESCE(v.price, p.price) * COALESCE(u.factor, 1)) + COALESCE((SELECT SUM(m.price_delta) FROM modifiers m WHERE m.id = ANY(i.modifiers)), 0)) * i.quantity,
+			COALESCE(i.round_id, 0), COALESCE(r.order_status, ''), COALESCE(i.transaction_id, 0), i.added_at
+		FROM tab_items i
+		JOIN products p ON p.id = i.product_id
+		LEFT JOIN tab_rounds r ON r.id = i.round_id
+		LEFT JOIN product_variants v ON v.id = i.variant_id
+		LEFT JOIN product_units u ON u.id = i.unit_id
+		WHERE i.tab_id = $1
+		ORDER BY i.id
+	`
+	rows, err := q.Query(query, tab.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tab.Items = make([]models.TabItem, 0)
+	tab.Total, tab.Unpaid = 0, 0
+	for rows.Next() {
+		var item models.TabItem
+		var modifiers pq.Int64Array
+		err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.VariantID, &item.VariantName, &item.UnitID, &item.UnitName, &item.Quantity, &modifiers, &item.Subtotal, &item.RoundID, &item.KitchenStatus, &item.TransactionID, &item.AddedAt)
+		if err != nil {
+			return err
+		}
+		item.Modifiers = toInts(modifiers)
+		tab.Items = append(tab.Items, item)
+		tab.Total += item.Subtotal
+		if item.TransactionID == 0 {
+			tab.Unpaid += item.Subtotal
+		}
+	}
+
+	return rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
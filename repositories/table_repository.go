@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+)
+
+type TableRepository struct {
+	db *sql.DB
+}
+
+func NewTableRepository(db *sql.DB) *TableRepository {
+	return &TableRepository{db: db}
+}
+
+func (repo *TableRepository) GetAll(area string) ([]models.DiningTable, error) {
+	query := "SELECT id, name, area FROM dining_tables"
+	args := []interface{}{}
+	if area != "" {
+		query += " WHERE area = $1"
+		args = append(args, area)
+	}
+	query += " ORDER BY area, name"
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make([]models.DiningTable, 0)
+	for rows.Next() {
+		var t models.DiningTable
+		err := rows.Scan(&t.ID, &t.Name, &t.Area)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+
+	return tables, nil
+}
+
+func (repo *TableRepository) Create(table *models.DiningTable) error {
+	query := "INSERT INTO dining_tables (name, area) VALUES ($1, $2) RETURNING id"
+	return repo.db.QueryRow(query, table.Name, table.Area).Scan(&table.ID)
+}
+
+func (repo *TableRepository) GetByID(id int) (*models.DiningTable, error) {
+	query := "SELECT id, name, area FROM dining_tables WHERE id = $1"
+
+	var t models.DiningTable
+	err := repo.db.QueryRow(query, id).Scan(&t.ID, &t.Name, &t.Area)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Table not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (repo *TableRepository) Update(table *models.DiningTable) error {
+	query := "UPDATE dining_tables SET name = $1, area = $2 WHERE id = $3"
+	result, err := repo.db.Exec(query, table.Name, table.Area, table.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Table not found")
+	}
+
+	return nil
+}
+
+// Delete - meja yang masih punya tab terbuka tidak bisa dihapus
+func (repo *TableRepository) Delete(id int) error {
+	query := "DELETE FROM dining_tables WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM tabs WHERE table_id = $1 AND status = 'open')"
+	result, err := repo.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("Table not found or still has an open tab")
+	}
+
+	return nil
+}
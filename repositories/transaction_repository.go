@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"kasir-api/models"
+	"math"
 )
 
 type TransactionRepository struct {
@@ -21,59 +22,267 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
+	if err := checkActiveProducts(tx, items); err != nil {
+		return nil, err
+	}
+	transaction, err := createTransaction(tx, items, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// createTransaction - jalur checkout di dalam tx, dipakai juga saat settle tab.
+// kitchenOrder = transaksi dengan item dapur/bar masuk antrian dapur, false untuk tab yang tiketnya sudah dibuat per ronde
+func createTransaction(tx *sql.Tx, items []models.CheckoutItem, kitchenOrder bool) (*models.Transaction, error) {
+	totalAmount, totalCost := 0, 0
 	details := make([]models.TransactionDetail, 0)
 
 	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+		detail, err := resolveItem(tx, item)
+		if err != nil {
+			return nil, err
+		}
 
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &stock)
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+		totalAmount += detail.Subtotal
+		totalCost += detail.Cost
+		details = append(details, *detail)
+	}
+
+	orderStatus := ""
+	if kitchenOrder {
+		for _, d := range details {
+			if d.Station != "" {
+				orderStatus = models.OrderStatusNew
+				break
+			}
 		}
+	}
+
+	transaction := models.Transaction{
+		TotalAmount: totalAmount,
+		TotalCost:   totalCost,
+		GrossProfit: totalAmount - totalCost,
+		Margin:      grossMargin(totalAmount, totalAmount-totalCost),
+		OrderStatus: orderStatus,
+		Details:     details,
+	}
+	err := tx.QueryRow("INSERT INTO transactions (total_amount, order_status) VALUES ($1, NULLIF($2, '')) RETURNING id, created_at", totalAmount, orderStatus).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range details {
+		details[i].TransactionID = transaction.ID
+		err = tx.QueryRow(`
+			INSERT INTO transaction_details (transaction_id, product_id, variant_id, unit_id, unit_name, quantity, base_quantity, subtotal, unit_cost)
+			VALUES ($1, $2, NULLIF($3::int, 0), NULLIF($4::int, 0), $5, $6, $7, $8, $9) RETURNING id
+		`, transaction.ID, details[i].ProductID, details[i].VariantID, details[i].UnitID, details[i].UnitName,
+			details[i].Quantity, details[i].BaseQuantity, details[i].Subtotal, details[i].UnitCost).Scan(&details[i].ID)
 		if err != nil {
 			return nil, err
 		}
 
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
+		for _, m := range details[i].Modifiers {
+			_, err = tx.Exec("INSERT INTO transaction_detail_modifiers (transaction_detail_id, modifier_id, name, price_delta) VALUES ($1, $2, $3, $4)",
+				details[i].ID, m.ModifierID, m.Name, m.PriceDelta)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, item.ProductID)
-		if err != nil {
+		for _, c := range details[i].Components {
+			_, err = tx.Exec("INSERT INTO transaction_detail_components (transaction_detail_id, product_id, quantity) VALUES ($1, $2, $3)",
+				details[i].ID, c.ComponentID, c.Quantity)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := deductStock(tx, &details[i]); err != nil {
 			return nil, err
 		}
+	}
 
-		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
-		})
+	return &transaction, nil
+}
+
+// checkActiveProducts - produk yang diarsip tidak bisa dijual lagi.
+// Tidak dicek di createTransaction supaya tab yang sudah berisi produk tersebut tetap bisa di-settle
+func checkActiveProducts(tx *sql.Tx, items []models.CheckoutItem) error {
+	for _, item := range items {
+		var archived bool
+		err := tx.QueryRow("SELECT archived_at IS NOT NULL FROM products WHERE id = $1", item.ProductID).Scan(&archived)
+		if err == sql.ErrNoRows {
+			return models.NewValidationError("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+		if archived {
+			return models.NewValidationError("product id %d is archived", item.ProductID)
+		}
 	}
+	return nil
+}
 
-	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+// resolveItem - cek produk, varian, modifier dan komponen bundle lalu hitung subtotal, stok belum diubah
+func resolveItem(tx *sql.Tx, item models.CheckoutItem) (*models.TransactionDetail, error) {
+	if item.Quantity <= 0 {
+		return nil, models.NewValidationError("quantity for product id %d must be greater than 0", item.ProductID)
+	}
+
+	var productPrice, unitCost, stock int
+	var productName, station string
+
+	err := tx.QueryRow(`
+		SELECT p.name, p.price, `+productCostColumn+`, p.stock, COALESCE(`+categoryStation("p.category_id")+`, '')
+		FROM products p
+		WHERE p.id = $1
+	`, item.ProductID).Scan(&productName, &productPrice, &unitCost, &stock, &station)
+	if err == sql.ErrNoRows {
+		return nil, models.NewValidationError("product id %d not found", item.ProductID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	for i := range details {
-		details[i].TransactionID = transactionID
-		_, err = tx.Exec("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4)",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
+	variantName := ""
+	if item.VariantID > 0 {
+		var variantProductID int
+		var variantPrice sql.NullInt64
+		err := tx.QueryRow("SELECT product_id, name, price FROM product_variants WHERE id = $1", item.VariantID).Scan(&variantProductID, &variantName, &variantPrice)
+		if err == sql.ErrNoRows || (err == nil && variantProductID != item.ProductID) {
+			return nil, models.NewValidationError("variant id %d not found for product id %d", item.VariantID, item.ProductID)
+		}
 		if err != nil {
 			return nil, err
 		}
+		if variantPrice.Valid {
+			productPrice = int(variantPrice.Int64)
+		}
+	} else {
+		var hasVariants bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", item.ProductID).Scan(&hasVariants)
+		if err != nil {
+			return nil, err
+		}
+		if hasVariants {
+			return nil, models.NewValidationError("variant_id is required for product id %d", item.ProductID)
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	// satuan jual: harga satuan kalau diisi, kalau tidak harga dasar dikali isi
+	unitName, factor, unitPrice, err := productUnit(tx, item.ProductID, item.UnitID)
+	if err != nil {
 		return nil, err
 	}
+	if unitPrice.Valid {
+		productPrice = int(unitPrice.Int64)
+	} else {
+		productPrice *= factor
+	}
+	unitCost *= factor
+	baseQty := item.Quantity * factor
 
-	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
+	groups, err := productModifierGroups(tx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	modifiers, err := selectModifiers(groups, item.Modifiers)
+	if err != nil {
+		return nil, fmt.Errorf("product id %d: %w", item.ProductID, err)
+	}
+	for _, m := range modifiers {
+		productPrice += m.PriceDelta
+	}
+
+	components, err := bundleComponents(tx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range components {
+		components[i].Quantity *= baseQty
+	}
+
+	// cek lot kedaluwarsa, untuk bundle yang dicek stok komponennya
+	warnings := make([]string, 0)
+	checks := []models.BundleComponent{{ComponentID: item.ProductID, Quantity: baseQty}}
+	variantID := item.VariantID
+	if len(components) > 0 {
+		checks = components
+		variantID = 0
+	}
+	for _, c := range checks {
+		warning, err := checkExpiredLots(tx, c.ComponentID, variantID, c.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	subtotal := productPrice * item.Quantity
+	cost := unitCost * item.Quantity
+	return &models.TransactionDetail{
+		ProductID:    item.ProductID,
+		ProductName:  productName,
+		VariantID:    item.VariantID,
+		VariantName:  variantName,
+		Station:      station,
+		UnitID:       item.UnitID,
+		UnitName:     unitName,
+		Quantity:     item.Quantity,
+		BaseQuantity: baseQty,
+		Subtotal:     subtotal,
+		UnitCost:     unitCost,
+		Cost:         cost,
+		GrossProfit:  subtotal - cost,
+		Margin:       grossMargin(subtotal, subtotal-cost),
+		Components:   components,
+		Modifiers:    modifiers,
+		Warnings:     warnings,
 	}, nil
 }
+
+// grossMargin - laba kotor dalam persen dari omzet, 2 desimal
+func grossMargin(revenue int, grossProfit int) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return math.Round(float64(grossProfit)*10000/float64(revenue)) / 100
+}
+
+// deductStock - kurangi stok varian/produk dan catat ke ledger, untuk bundle yang berkurang stok komponennya
+func deductStock(tx *sql.Tx, detail *models.TransactionDetail) error {
+	if len(detail.Components) > 0 {
+		for _, c := range detail.Components {
+			err := changeStock(tx, &models.StockMovement{
+				ProductID:     c.ComponentID,
+				QuantityDelta: -c.Quantity,
+				Reason:        models.MovementSale,
+				ReferenceType: "transaction",
+				ReferenceID:   detail.TransactionID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return changeStock(tx, &models.StockMovement{
+		ProductID:     detail.ProductID,
+		VariantID:     detail.VariantID,
+		QuantityDelta: -detail.BaseQuantity,
+		Reason:        models.MovementSale,
+		ReferenceType: "transaction",
+		ReferenceID:   detail.TransactionID,
+	})
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type UnitRepository struct {
+	db *sql.DB
+}
+
+func NewUnitRepository(db *sql.DB) *UnitRepository {
+	return &UnitRepository{db: db}
+}
+
+func (repo *UnitRepository) GetByProduct(productID int) ([]models.ProductUnit, error) {
+	rows, err := repo.db.Query("SELECT id, product_id, name, factor, price, COALESCE(barcode, '') FROM product_units WHERE product_id = $1 ORDER BY factor, name", productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	units := make([]models.ProductUnit, 0)
+	for rows.Next() {
+		u, err := scanUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, *u)
+	}
+
+	return units, nil
+}
+
+func (repo *UnitRepository) GetByID(productID, id int) (*models.ProductUnit, error) {
+	row := repo.db.QueryRow("SELECT id, product_id, name, factor, price, COALESCE(barcode, '') FROM product_units WHERE id = $1 AND product_id = $2", id, productID)
+	u, err := scanUnit(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Unit not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Create - barcode satuan tidak boleh sama dengan barcode produk mana pun
+func (repo *UnitRepository) Create(unit *models.ProductUnit) error {
+	query := `
+		INSERT INTO product_units (product_id, name, factor, price, barcode)
+		SELECT $1, $2, $3, $4, NULLIF($5, '')
+		WHERE NOT EXISTS (SELECT 1 FROM products WHERE barcode = NULLIF($5, ''))
+		RETURNING id
+	`
+	err := repo.db.QueryRow(query, unit.ProductID, unit.Name, unit.Factor, unit.Price, unit.Barcode).Scan(&unit.ID)
+	return unitWriteError(err)
+}
+
+func (repo *UnitRepository) Update(unit *models.ProductUnit) error {
+	query := `
+		UPDATE product_units SET name = $1, factor = $2, price = $3, barcode = NULLIF($4, '')
+		WHERE id = $5 AND product_id = $6 AND NOT EXISTS (SELECT 1 FROM products WHERE barcode = NULLIF($4, ''))
+		RETURNING id
+	`
+	err := repo.db.QueryRow(query, unit.Name, unit.Factor, unit.Price, unit.Barcode, unit.ID, unit.ProductID).Scan(&unit.ID)
+	if err == sql.ErrNoRows {
+		return errors.New("Unit not found or barcode is used by a product")
+	}
+	return unitWriteError(err)
+}
+
+// Delete - satuan yang masih dipakai tab atau PO tidak bisa dihapus, riwayat transaksi tetap menyimpan nama satuan
+func (repo *UnitRepository) Delete(productID, id int) error {
+	result, err := repo.db.Exec("DELETE FROM product_units WHERE id = $1 AND product_id = $2", id, productID)
+	if isForeignKeyViolation(err) {
+		return errors.New("Unit is still used by tabs or purchase orders")
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("Unit not found")
+	}
+	return nil
+}
+
+// LookupBarcode - cari barcode di produk aktif dulu, lalu di satuan
+func (repo *UnitRepository) LookupBarcode(code string) (*models.BarcodeMatch, error) {
+	var m models.BarcodeMatch
+	err := repo.db.QueryRow("SELECT id, name, base_unit, price FROM products WHERE barcode = $1 AND archived_at IS NULL", code).Scan(&m.ProductID, &m.ProductName, &m.UnitName, &m.Price)
+	if err == nil {
+		m.Factor = 1
+		return &m, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	err = repo.db.QueryRow(`
+		SELECT p.id, p.name, u.id, u.name, u.factor, COALESCE(u.price, p.price * u.factor)
+		FROM product_units u
+		JOIN products p ON p.id = u.product_id
+		WHERE u.barcode = $1 AND p.archived_at IS NULL
+	`, code).Scan(&m.ProductID, &m.ProductName, &m.UnitID, &m.UnitName, &m.Factor, &m.Price)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Barcode not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// productUnit - nama dan isi satuan untuk transaksi/PO, unitID 0 = satuan dasar
+func productUnit(tx *sql.Tx, productID int, unitID int) (string, int, sql.NullInt64, error) {
+	var price sql.NullInt64
+	if unitID == 0 {
+		return "", 1, price, nil
+	}
+
+	var name string
+	var factor int
+	err := tx.QueryRow("SELECT name, factor, price FROM product_units WHERE id = $1 AND product_id = $2", unitID, productID).Scan(&name, &factor, &price)
+	if err == sql.ErrNoRows {
+		return "", 0, price, models.NewValidationError("unit id %d not found for product id %d", unitID, productID)
+	}
+	return name, factor, price, err
+}
+
+func unitWriteError(err error) error {
+	if err == sql.ErrNoRows {
+		return errors.New("Barcode is used by a product")
+	}
+	if isUniqueViolation(err) {
+		return errors.New("Unit name or barcode already exists")
+	}
+	return err
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+func scanUnit(row rowScanner) (*models.ProductUnit, error) {
+	var u models.ProductUnit
+	var price sql.NullInt64
+	if err := row.Scan(&u.ID, &u.ProductID, &u.Name, &u.Factor, &price, &u.Barcode); err != nil {
+		return nil, err
+	}
+	if price.Valid {
+		p := int(price.Int64)
+		u.Price = &p
+	}
+	return &u, nil
+}
@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+
+	"github.com/lib/pq"
+)
+
+type VariantRepository struct {
+	db *sql.DB
+}
+
+func NewVariantRepository(db *sql.DB) *VariantRepository {
+	return &VariantRepository{db: db}
+}
+
+// GetOptions - dimensi opsi produk, urut sesuai posisi
+func (repo *VariantRepository) GetOptions(productID int) ([]models.ProductOption, error) {
+	query := `SELECT name, "values" FROM product_options WHERE product_id = $1 ORDER BY position, id`
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := make([]models.ProductOption, 0)
+	for rows.Next() {
+		var o models.ProductOption
+		err := rows.Scan(&o.Name, pq.Array(&o.Values))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+
+	return options, nil
+}
+
+// SetOptions - ganti semua dimensi opsi produk
+func (repo *VariantRepository) SetOptions(productID int, options []models.ProductOption) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM product_options WHERE product_id = $1", productID)
+	if err != nil {
+		return err
+	}
+
+	for i, o := range options {
+		_, err = tx.Exec(`INSERT INTO product_options (product_id, name, position, "values") VALUES ($1, $2, $3, $4)`,
+			productID, o.Name, i, pq.Array(o.Values))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo *VariantRepository) GetByProduct(productID int) ([]models.ProductVariant, error) {
+	query := "SELECT id, product_id, sku, name, options, price, stock FROM product_variants WHERE product_id = $1 ORDER BY id"
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make([]models.ProductVariant, 0)
+	for rows.Next() {
+		v, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, *v)
+	}
+
+	return variants, nil
+}
+
+func (repo *VariantRepository) GetByID(id int) (*models.ProductVariant, error) {
+	query := "SELECT id, product_id, sku, name, options, price, stock FROM product_variants WHERE id = $1"
+	v, err := scanVariant(repo.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("Variant not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (repo *VariantRepository) Create(variant *models.ProductVariant, user string) error {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO product_variants (product_id, sku, name, options, price, stock) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+	err = tx.QueryRow(query, variant.ProductID, variant.SKU, variant.Name, options, variant.Price, variant.Stock).Scan(&variant.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := syncParentStock(tx, variant.ProductID); err != nil {
+		return err
+	}
+
+	err = recordMovement(tx, &models.StockMovement{
+		ProductID:     variant.ProductID,
+		VariantID:     variant.ID,
+		QuantityDelta: variant.Stock,
+		Reason:        models.MovementAdjustment,
+		User:          user,
+		Note:          "initial stock",
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update - perubahan stok lewat update varian dicatat sebagai adjustment
+func (repo *VariantRepository) Update(variant *models.ProductVariant, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldStock int
+	err = tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE", variant.ID, variant.ProductID).Scan(&oldStock)
+	if err == sql.ErrNoRows {
+		return errors.New("Variant not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE product_variants SET sku = $1, price = $2, stock = $3 WHERE id = $4"
+	_, err = tx.Exec(query, variant.SKU, variant.Price, variant.Stock, variant.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := syncParentStock(tx, variant.ProductID); err != nil {
+		return err
+	}
+
+	err = recordMovement(tx, &models.StockMovement{
+		ProductID:     variant.ProductID,
+		VariantID:     variant.ID,
+		QuantityDelta: variant.Stock - oldStock,
+		Reason:        models.MovementAdjustment,
+		User:          user,
+		Note:          "stock set via variant update",
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete - sisa stok varian yang dihapus dicatat keluar dari ledger
+func (repo *VariantRepository) Delete(productID, id int, user string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var stock int
+	err = tx.QueryRow("DELETE FROM product_variants WHERE id = $1 AND product_id = $2 RETURNING stock", id, productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return errors.New("Variant not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := syncParentStock(tx, productID); err != nil {
+		return err
+	}
+
+	// varian sudah terhapus, jadi balance_after yang tercatat adalah stok produk induk
+	err = recordMovement(tx, &models.StockMovement{
+		ProductID:     productID,
+		QuantityDelta: -stock,
+		Reason:        models.MovementAdjustment,
+		User:          user,
+		Note:          fmt.Sprintf("variant id %d deleted", id),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// syncParentStock - stok produk induk = total stok semua variannya
+func syncParentStock(tx *sql.Tx, productID int) error {
+	_, err := tx.Exec(`
+		UPDATE products
+		SET stock = COALESCE((SELECT SUM(stock) FROM product_variants WHERE product_id = $1), 0)
+		WHERE id = $1
+	`, productID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVariant(row rowScanner) (*models.ProductVariant, error) {
+	var v models.ProductVariant
+	var options []byte
+	var price sql.NullInt64
+	err := row.Scan(&v.ID, &v.ProductID, &v.SKU, &v.Name, &options, &price, &v.Stock)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(options, &v.Options); err != nil {
+		return nil, err
+	}
+	if price.Valid {
+		p := int(price.Int64)
+		v.Price = &p
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxTagLength            = 50
+	maxAttributeValueLength = 500
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type AttributeService struct {
+	repo *repositories.AttributeRepository
+}
+
+func NewAttributeService(repo *repositories.AttributeRepository) *AttributeService {
+	return &AttributeService{repo: repo}
+}
+
+func (s *AttributeService) GetAll() ([]models.AttributeDefinition, error) {
+	return s.repo.GetAll()
+}
+
+func (s *AttributeService) Create(d *models.AttributeDefinition) error {
+	d.Key = strings.TrimSpace(d.Key)
+	if !attributeKeyPattern.MatchString(d.Key) {
+		return errors.New("Invalid key, use lowercase letters, digits and underscore, starting with a letter")
+	}
+	switch d.Type {
+	case models.AttributeText, models.AttributeNumber, models.AttributeBoolean, models.AttributeDate, models.AttributeSelect:
+	default:
+		return errors.New("Invalid type, use text, number, boolean, date or select")
+	}
+	if err := validateAttributeDefinition(d); err != nil {
+		return err
+	}
+	return s.repo.Create(d)
+}
+
+func (s *AttributeService) GetByID(id int) (*models.AttributeDefinition, error) {
+	return s.repo.GetByID(id)
+}
+
+// Update - key dan type diambil dari data lama, mengubahnya berarti membuat atribut baru
+func (s *AttributeService) Update(d *models.AttributeDefinition) error {
+	current, err := s.repo.GetByID(d.ID)
+	if err != nil {
+		return err
+	}
+	if (d.Key != "" && d.Key != current.Key) || (d.Type != "" && d.Type != current.Type) {
+		return errors.New("key and type cannot be changed")
+	}
+	d.Key, d.Type = current.Key, current.Type
+	if err := validateAttributeDefinition(d); err != nil {
+		return err
+	}
+	return s.repo.Update(d)
+}
+
+func (s *AttributeService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validateAttributeDefinition(d *models.AttributeDefinition) error {
+	d.Label = strings.TrimSpace(d.Label)
+	if d.Label == "" {
+		return errors.New("label is required")
+	}
+
+	options := make([]string, 0, len(d.Options))
+	seen := make(map[string]bool)
+	for _, o := range d.Options {
+		o = strings.TrimSpace(o)
+		if o == "" || seen[o] {
+			continue
+		}
+		seen[o] = true
+		options = append(options, o)
+	}
+	if d.Type == models.AttributeSelect && len(options) == 0 {
+		return errors.New("options are required for select attributes")
+	}
+	if d.Type != models.AttributeSelect && len(options) > 0 {
+		return errors.New("options are only allowed for select attributes")
+	}
+	d.Options = options
+	return nil
+}
+
+// normalizeTags - huruf kecil, tanpa spasi di ujung, tanpa duplikat, urut abjad
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// validateAttributes - setiap key harus terdefinisi dan nilainya sesuai type, atribut required wajib terisi
+// kalau requireAll (create, atau patch yang mengubah attributes) supaya definisi required baru tidak memblok
+// update produk lama. Nilai dari JSON (angka = float64) dirapikan di tempat, tanggal disimpan sebagai "2006-01-02"
+func validateAttributes(definitions map[string]models.AttributeDefinition, attributes map[string]interface{}, requireAll bool) error {
+	for key, value := range attributes {
+		d, ok := definitions[key]
+		if !ok {
+			return fmt.Errorf("unknown attribute %q", key)
+		}
+		if value == nil {
+			delete(attributes, key)
+			continue
+		}
+
+		switch d.Type {
+		case models.AttributeNumber:
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("attribute %s must be a number", key)
+			}
+		case models.AttributeBoolean:
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("attribute %s must be true or false", key)
+			}
+		default:
+			text, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("attribute %s must be a string", key)
+			}
+			text = strings.TrimSpace(text)
+			if text == "" {
+				delete(attributes, key)
+				continue
+			}
+			parsed, err := parseAttributeValue(d, text)
+			if err != nil {
+				return err
+			}
+			attributes[key] = parsed
+		}
+	}
+
+	if !requireAll {
+		return nil
+	}
+	for key, d := range definitions {
+		if _, ok := attributes[key]; d.Required && !ok {
+			return fmt.Errorf("attribute %s is required", key)
+		}
+	}
+	return nil
+}
+
+// parseAttributeValue - nilai atribut dari teks (isi sel import atau string JSON) sesuai type definisinya
+func parseAttributeValue(d models.AttributeDefinition, text string) (interface{}, error) {
+	switch d.Type {
+	case models.AttributeNumber:
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be a number", d.Key)
+		}
+		return n, nil
+	case models.AttributeBoolean:
+		switch strings.ToLower(text) {
+		case "true", "yes", "ya", "1":
+			return true, nil
+		case "false", "no", "tidak", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("attribute %s must be true or false", d.Key)
+	case models.AttributeDate:
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be a date in YYYY-MM-DD format", d.Key)
+		}
+		return date.Format("2006-01-02"), nil
+	case models.AttributeSelect:
+		for _, o := range d.Options {
+			if strings.EqualFold(o, text) {
+				return o, nil
+			}
+		}
+		return nil, fmt.Errorf("attribute %s must be one of %s", d.Key, strings.Join(d.Options, ", "))
+	default:
+		if len(text) > maxAttributeValueLength {
+			return nil, fmt.Errorf("attribute %s is longer than %d characters", d.Key, maxAttributeValueLength)
+		}
+		return text, nil
+	}
+}
+
+// formatAttributeValue - kebalikan parseAttributeValue untuk export
+func formatAttributeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
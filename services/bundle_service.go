@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type BundleService struct {
+	repo        *repositories.BundleRepository
+	productRepo *repositories.ProductRepository
+	variantRepo *repositories.VariantRepository
+}
+
+func NewBundleService(repo *repositories.BundleRepository, productRepo *repositories.ProductRepository, variantRepo *repositories.VariantRepository) *BundleService {
+	return &BundleService{repo: repo, productRepo: productRepo, variantRepo: variantRepo}
+}
+
+func (s *BundleService) GetComponents(bundleID int) ([]models.BundleComponent, error) {
+	if _, err := s.productRepo.GetByID(bundleID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetComponents(bundleID)
+}
+
+// SetComponents - komponen harus produk biasa: bukan bundle dan tidak punya varian
+func (s *BundleService) SetComponents(bundleID int, components []models.BundleComponent) ([]models.BundleComponent, error) {
+	if _, err := s.productRepo.GetByID(bundleID); err != nil {
+		return nil, err
+	}
+
+	if len(components) > 0 {
+		isComponent, err := s.repo.IsComponent(bundleID)
+		if err != nil {
+			return nil, err
+		}
+		if isComponent {
+			return nil, errors.New("Product is a component of another bundle and cannot be a bundle")
+		}
+		if err := s.ensureNoVariants(bundleID); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, c := range components {
+		if c.ComponentID == bundleID {
+			return nil, errors.New("Bundle cannot contain itself")
+		}
+		if seen[c.ComponentID] {
+			return nil, fmt.Errorf("Duplicate component id %d", c.ComponentID)
+		}
+		seen[c.ComponentID] = true
+
+		if c.Quantity <= 0 {
+			return nil, fmt.Errorf("Quantity for component id %d must be greater than 0", c.ComponentID)
+		}
+
+		component, err := s.productRepo.GetByID(c.ComponentID)
+		if err != nil {
+			return nil, fmt.Errorf("Component id %d not found", c.ComponentID)
+		}
+		if component.IsBundle {
+			return nil, fmt.Errorf("Component id %d is a bundle", c.ComponentID)
+		}
+		if err := s.ensureNoVariants(c.ComponentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SetComponents(bundleID, components); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetComponents(bundleID)
+}
+
+func (s *BundleService) ensureNoVariants(productID int) error {
+	variants, err := s.variantRepo.GetByProduct(productID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return fmt.Errorf("Product id %d has variants and cannot be used in a bundle", productID)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"errors"
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"strings"
 )
 
 type CategoryService struct {
@@ -13,11 +15,44 @@ func NewCategoryService(repo *repositories.CategoryRepository) *CategoryService
 	return &CategoryService{repo: repo}
 }
 
-func (s *CategoryService) GetAll() ([]models.Category, error) {
-	return s.repo.GetAll()
+func (s *CategoryService) GetAll(filter models.CategoryFilter) (*models.CategoryPage, error) {
+	var err error
+	filter.Page, filter.PageSize, err = normalizePage(filter.Page, filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateListStatus(filter.Status); err != nil {
+		return nil, err
+	}
+	switch filter.Sort {
+	case "", "name", "updated_at":
+	default:
+		return nil, errors.New("Invalid sort, use name or updated_at")
+	}
+
+	categories, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CategoryPage{Categories: categories, Pagination: newPagination(filter.Page, filter.PageSize, total)}, nil
 }
 
 func (s *CategoryService) Create(data *models.Category) error {
+	if err := validateStation(data.Station); err != nil {
+		return err
+	}
+	if data.ParentID < 0 {
+		return errors.New("Invalid parent_id")
+	}
+	if data.ParentID > 0 {
+		parent, err := s.repo.GetByID(data.ParentID)
+		if err != nil {
+			return errors.New("parent category not found")
+		}
+		if parent.ArchivedAt != nil {
+			return errors.New("parent category is archived")
+		}
+	}
 	return s.repo.Create(data)
 }
 
@@ -25,10 +60,127 @@ func (s *CategoryService) GetByID(id int) (*models.Category, error) {
 	return s.repo.GetByID(id)
 }
 
+// Tree - kategori sebagai pohon, kategori yang parent-nya tidak ikut terfilter (mis. diarsip) jadi akar
+func (s *CategoryService) Tree(status string) ([]models.Category, error) {
+	if err := validateListStatus(status); err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.GetTree(status)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int][]models.Category)
+	found := make(map[int]bool)
+	for _, c := range categories {
+		found[c.ID] = true
+	}
+	roots := make([]models.Category, 0)
+	for _, c := range categories {
+		if c.ParentID > 0 && found[c.ParentID] {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var attach func(nodes []models.Category)
+	attach = func(nodes []models.Category) {
+		for i := range nodes {
+			nodes[i].Children = children[nodes[i].ID]
+			attach(nodes[i].Children)
+		}
+	}
+	attach(roots)
+	return roots, nil
+}
+
+// Move - pindahkan kategori beserta seluruh sub kategorinya
+func (s *CategoryService) Move(id int, parentID int, version int) (*models.Category, error) {
+	if parentID < 0 {
+		return nil, errors.New("Invalid parent_id")
+	}
+	if err := s.repo.Move(id, parentID, version); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
 func (s *CategoryService) Update(Category *models.Category) error {
+	if err := validateStation(Category.Station); err != nil {
+		return err
+	}
 	return s.repo.Update(Category)
 }
 
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+// categoryPatchFields - parent diubah lewat Move supaya cek siklus tetap di satu tempat
+var categoryPatchFields = []string{"name", "description", "station"}
+
+// Patch - JSON Merge Patch untuk kategori
+func (s *CategoryService) Patch(id int, data []byte, version int) (*models.Category, error) {
+	patch, err := decodeMergePatch(data, categoryPatchFields)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 && version != category.Version {
+		return nil, models.ErrVersionConflict
+	}
+
+	err = applyMergePatch(patch, map[string]interface{}{
+		"name":        &category.Name,
+		"description": &category.Description,
+		"station":     &category.Station,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(category.Name) == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+	if err := validateStation(category.Station); err != nil {
+		return nil, err
+	}
+
+	// Update menulis semua kolom, version hasil baca di atas menjaga field lain tidak tertimpa nilai lama
+	if err := s.repo.Update(category); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Archive - pengganti hard delete, produk di kategori ini tidak ikut diarsip tapi dipindah ke reassignTo
+func (s *CategoryService) Archive(id int, version int, reassignTo int) (*models.CategoryDeleteResult, error) {
+	if reassignTo < 0 {
+		return nil, errors.New("Invalid reassign_to")
+	}
+	return s.repo.Archive(id, version, reassignTo)
+}
+
+func (s *CategoryService) Restore(id int, version int) (*models.Category, error) {
+	if err := s.repo.Restore(id, version); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *CategoryService) Purge(id int, version int, reassignTo int) (*models.CategoryDeleteResult, error) {
+	if reassignTo < 0 {
+		return nil, errors.New("Invalid reassign_to")
+	}
+	return s.repo.Purge(id, version, reassignTo)
+}
+
+// validateStation - kosong berarti produk di kategori ini tidak dikirim ke dapur
+func validateStation(station string) error {
+	switch station {
+	case "", models.StationKitchen, models.StationBar:
+		return nil
+	default:
+		return errors.New("Invalid station, use kitchen or bar")
+	}
 }
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sync"
+)
+
+type KitchenService struct {
+	repo *repositories.KitchenRepository
+
+	mu          sync.Mutex
+	subscribers map[chan models.KitchenEvent]struct{}
+}
+
+func NewKitchenService(repo *repositories.KitchenRepository) *KitchenService {
+	return &KitchenService{
+		repo:        repo,
+		subscribers: make(map[chan models.KitchenEvent]struct{}),
+	}
+}
+
+// ValidateStation - station kosong (semua) atau kitchen/bar, tanpa query ke database
+func (s *KitchenService) ValidateStation(station string) error {
+	return validateKitchenStation(station)
+}
+
+func (s *KitchenService) GetQueue(station string) ([]models.KitchenTicket, error) {
+	if err := validateKitchenStation(station); err != nil {
+		return nil, err
+	}
+	return s.repo.GetQueue(station)
+}
+
+func (s *KitchenService) GetTicket(transactionID int, station string) (*models.KitchenTicket, error) {
+	return s.repo.GetTicket(transactionID, station)
+}
+
+// GetEventTicket - tiket dari event stream, pesanan checkout atau ronde tab
+func (s *KitchenService) GetEventTicket(event models.KitchenEvent, station string) (*models.KitchenTicket, error) {
+	if event.TabRoundID > 0 {
+		return s.repo.GetRoundTicket(event.TabRoundID, station)
+	}
+	return s.repo.GetTicket(event.TransactionID, station)
+}
+
+// UpdateStatus - status hanya boleh maju: new -> preparing -> ready -> served
+func (s *KitchenService) UpdateStatus(transactionID int, status string) error {
+	current, err := s.repo.GetStatus(transactionID)
+	if err != nil {
+		return err
+	}
+	if err := validateStatusChange(current, status); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateStatus(transactionID, current, status); err != nil {
+		return err
+	}
+
+	s.Notify(models.KitchenEvent{TransactionID: transactionID, OrderStatus: status})
+	return nil
+}
+
+// UpdateRoundStatus - sama seperti UpdateStatus untuk ronde tab
+func (s *KitchenService) UpdateRoundStatus(roundID int, status string) error {
+	current, err := s.repo.GetRoundStatus(roundID)
+	if err != nil {
+		return err
+	}
+	if err := validateStatusChange(current, status); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateRoundStatus(roundID, current, status); err != nil {
+		return err
+	}
+
+	s.Notify(models.KitchenEvent{TabRoundID: roundID, OrderStatus: status})
+	return nil
+}
+
+// Subscribe - channel event untuk kitchen display, panggil cancel saat koneksi ditutup
+func (s *KitchenService) Subscribe() (<-chan models.KitchenEvent, func()) {
+	ch := make(chan models.KitchenEvent, 16)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+// Notify - kirim event ke semua subscriber, subscriber yang lambat dilewati
+func (s *KitchenService) Notify(event models.KitchenEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func validateStatusChange(current, status string) error {
+	next := statusIndex(status)
+	if next < 0 {
+		return fmt.Errorf("Invalid status, use one of %v", models.OrderStatuses)
+	}
+	if next <= statusIndex(current) {
+		return fmt.Errorf("Cannot change status from %s to %s", current, status)
+	}
+	return nil
+}
+
+func statusIndex(status string) int {
+	for i, st := range models.OrderStatuses {
+		if st == status {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateKitchenStation(station string) error {
+	if station == "" {
+		return nil
+	}
+	return validateStation(station)
+}
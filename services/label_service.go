@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/labels"
+	"kasir-api/repositories"
+)
+
+type LabelService struct {
+	repo *repositories.ProductRepository
+}
+
+func NewLabelService(repo *repositories.ProductRepository) *LabelService {
+	return &LabelService{repo: repo}
+}
+
+// ProductLabel - label rak untuk satu produk
+func (s *LabelService) ProductLabel(id int, kind string) (*labels.Label, error) {
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := productBarcode(product, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &labels.Label{Name: product.Name, Price: product.Price, Barcode: code}, nil
+}
+
+// Sheet - label untuk daftar produk dan/atau satu kategori. Produk tanpa barcode ditolak supaya
+// lembar label tidak diam-diam kurang, kecuali skipMissing: produk tersebut dilewati dan ID-nya dikembalikan
+func (s *LabelService) Sheet(ids []int, categoryID int, kind string, skipMissing bool) ([]labels.Label, []int, error) {
+	if len(ids) == 0 && categoryID == 0 {
+		return nil, nil, errors.New("product_ids or category_id is required")
+	}
+
+	products, err := s.repo.GetForLabels(ids, categoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skipped := make([]int, 0)
+	for _, p := range products {
+		if p.Barcode == "" {
+			skipped = append(skipped, p.ID)
+		}
+	}
+	if len(skipped) > 0 && !skipMissing {
+		return nil, skipped, fmt.Errorf("%d products have no barcode, assign internal barcodes with POST /v2/products/barcodes or use skip_missing=true", len(skipped))
+	}
+
+	result := make([]labels.Label, 0, len(products))
+	for i := range products {
+		if products[i].Barcode == "" {
+			continue
+		}
+		code, err := productBarcode(&products[i], kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, labels.Label{Name: products[i].Name, Price: products[i].Price, Barcode: code})
+	}
+
+	if len(result) == 0 {
+		return nil, skipped, errors.New("No products with a barcode found")
+	}
+
+	return result, skipped, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type ModifierService struct {
+	repo        *repositories.ModifierRepository
+	productRepo *repositories.ProductRepository
+}
+
+func NewModifierService(repo *repositories.ModifierRepository, productRepo *repositories.ProductRepository) *ModifierService {
+	return &ModifierService{repo: repo, productRepo: productRepo}
+}
+
+func (s *ModifierService) GetAllGroups() ([]models.ModifierGroup, error) {
+	return s.repo.GetAllGroups()
+}
+
+func (s *ModifierService) GetGroupByID(id int) (*models.ModifierGroup, error) {
+	return s.repo.GetGroupByID(id)
+}
+
+func (s *ModifierService) CreateGroup(group *models.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	if err := s.repo.CreateGroup(group); err != nil {
+		return err
+	}
+	group.Modifiers = []models.Modifier{}
+	return nil
+}
+
+func (s *ModifierService) UpdateGroup(group *models.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateGroup(group); err != nil {
+		return err
+	}
+
+	updated, err := s.repo.GetGroupByID(group.ID)
+	if err != nil {
+		return err
+	}
+	*group = *updated
+	return nil
+}
+
+func (s *ModifierService) DeleteGroup(id int) error {
+	return s.repo.DeleteGroup(id)
+}
+
+func (s *ModifierService) CreateModifier(modifier *models.Modifier) error {
+	if strings.TrimSpace(modifier.Name) == "" {
+		return errors.New("Modifier name is required")
+	}
+	if _, err := s.repo.GetGroupByID(modifier.GroupID); err != nil {
+		return err
+	}
+	return s.repo.CreateModifier(modifier)
+}
+
+func (s *ModifierService) UpdateModifier(modifier *models.Modifier) error {
+	if strings.TrimSpace(modifier.Name) == "" {
+		return errors.New("Modifier name is required")
+	}
+	return s.repo.UpdateModifier(modifier)
+}
+
+func (s *ModifierService) DeleteModifier(groupID, id int) error {
+	return s.repo.DeleteModifier(groupID, id)
+}
+
+// GetForProduct - grup modifier yang berlaku untuk produk, untuk ditampilkan di POS
+func (s *ModifierService) GetForProduct(productID int) ([]models.ModifierGroup, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGroupsForProduct(productID)
+}
+
+func validateModifierGroup(group *models.ModifierGroup) error {
+	if strings.TrimSpace(group.Name) == "" {
+		return errors.New("Modifier group name is required")
+	}
+	if group.MinSelect < 0 || group.MaxSelect < 0 {
+		return errors.New("min_select and max_select cannot be negative")
+	}
+	if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+		return errors.New("min_select cannot be greater than max_select")
+	}
+	if group.ProductIDs == nil {
+		group.ProductIDs = []int{}
+	}
+	if group.CategoryIDs == nil {
+		group.CategoryIDs = []int{}
+	}
+	return nil
+}
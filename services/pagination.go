@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// normalizePage - page default 1, page_size default 50 dan maksimal 200
+func normalizePage(page, pageSize int) (int, int, error) {
+	if page < 0 || pageSize < 0 {
+		return 0, 0, errors.New("page and page_size cannot be negative")
+	}
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	return page, min(pageSize, maxPageSize), nil
+}
+
+func validateListStatus(status string) error {
+	switch status {
+	case "", models.ListActive, models.ListArchived, models.ListAll:
+		return nil
+	default:
+		return errors.New("Invalid status, use active, archived or all")
+	}
+}
+
+func newPagination(page, pageSize, total int) models.Pagination {
+	return models.Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+}
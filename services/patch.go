@@ -0,0 +1,60 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// decodeMergePatch - body JSON Merge Patch (RFC 7386), hanya object satu level dengan field yang diizinkan
+func decodeMergePatch(data []byte, allowed []string) (map[string]json.RawMessage, error) {
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(data, &patch); err != nil || patch == nil {
+		return nil, errors.New("Invalid patch, body must be a JSON object")
+	}
+	if len(patch) == 0 {
+		return nil, errors.New("patch has no fields")
+	}
+
+	known := make(map[string]bool, len(allowed))
+	for _, f := range allowed {
+		known[f] = true
+	}
+	for field := range patch {
+		if !known[field] {
+			fields := append([]string(nil), allowed...)
+			sort.Strings(fields)
+			return nil, fmt.Errorf("field %q cannot be patched, use %s", field, strings.Join(fields, ", "))
+		}
+	}
+	return patch, nil
+}
+
+// applyMergePatch - isi field target dari patch, null = kembali ke nilai kosong (default diisi validasi).
+// Target map digabung per key oleh json.Unmarshal, key bernilai null tersimpan sebagai nil untuk dihapus validasi
+func applyMergePatch(patch map[string]json.RawMessage, targets map[string]interface{}) error {
+	for field, raw := range patch {
+		target := targets[field]
+		if string(raw) == "null" {
+			switch t := target.(type) {
+			case *string:
+				*t = ""
+			case *int:
+				*t = 0
+			case *bool:
+				*t = false
+			case *[]string:
+				*t = nil
+			case *map[string]interface{}:
+				*t = nil
+			}
+			continue
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("Invalid value for %s", field)
+		}
+	}
+	return nil
+}
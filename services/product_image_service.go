@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"kasir-api/imaging"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/storage"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	MaxImageSize     = 5 << 20
+	maxImagePixels   = 40_000_000
+	imageMediumSide  = 600
+	imageThumbSide   = 200
+	imageJPEGQuality = 85
+)
+
+// ekstensi file original per content type yang diterima
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+type ProductImageService struct {
+	repo    *repositories.ProductRepository
+	storage storage.Storage
+}
+
+func NewProductImageService(repo *repositories.ProductRepository, storage storage.Storage) *ProductImageService {
+	return &ProductImageService{repo: repo, storage: storage}
+}
+
+// Upload - simpan gambar original beserta versi medium dan thumbnail (JPEG), gambar lama dihapus
+func (s *ProductImageService) Upload(productID int, data []byte) (*models.Product, error) {
+	if _, err := s.repo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("image is required")
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("image cannot be larger than %d MB", MaxImageSize>>20)
+	}
+
+	// content type dari isi file, bukan dari header/ekstensi kiriman client
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, errors.New("Invalid image type, use jpeg, png or gif")
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("Invalid image file")
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, errors.New("image dimensions are too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("Invalid image file")
+	}
+
+	base := fmt.Sprintf("products/%d/%d", productID, time.Now().UnixNano())
+	keys := []string{base + "_original." + ext, base + "_medium.jpg", base + "_thumb.jpg"}
+	contents := [][]byte{data}
+	for _, side := range []int{imageMediumSide, imageThumbSide} {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, imaging.Fit(img, side), imageJPEGQuality); err != nil {
+			return nil, err
+		}
+		contents = append(contents, buf.Bytes())
+	}
+
+	saved := make([]string, 0, len(keys))
+	for i, key := range keys {
+		if err := s.storage.Save(key, bytes.NewReader(contents[i])); err != nil {
+			deleteImageFiles(s.storage, saved)
+			return nil, err
+		}
+		saved = append(saved, key)
+	}
+
+	old, err := s.repo.SetImage(productID, keys[0], keys[1], keys[2])
+	if err != nil {
+		deleteImageFiles(s.storage, saved)
+		return nil, err
+	}
+	deleteImageFiles(s.storage, old)
+
+	return s.product(productID)
+}
+
+// Delete - hapus gambar produk dari database dan storage
+func (s *ProductImageService) Delete(productID int) (*models.Product, error) {
+	old, err := s.repo.SetImage(productID, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	deleteImageFiles(s.storage, old)
+
+	return s.product(productID)
+}
+
+func (s *ProductImageService) product(id int) (*models.Product, error) {
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	productImageURLs(s.storage, product)
+	return product, nil
+}
+
+// deleteImageFiles - file yang gagal dihapus cukup dicatat, data produk sudah tidak merujuk ke sana
+func deleteImageFiles(st storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := st.Delete(key); err != nil {
+			log.Println("Failed to delete image", key, ":", err)
+		}
+	}
+}
+
+// productImageURLs - repository mengisi field gambar dengan key storage, di sini diubah jadi URL publik
+func productImageURLs(st storage.Storage, p *models.Product) {
+	for _, field := range []*string{&p.ImageURL, &p.ImageMediumURL, &p.ImageThumbURL} {
+		if *field != "" {
+			*field = st.URL(*field)
+		}
+	}
+}
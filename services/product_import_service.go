@@ -0,0 +1,396 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/spreadsheet"
+	"strconv"
+	"strings"
+)
+
+type ProductImportService struct {
+	repo          *repositories.ProductRepository
+	variantRepo   *repositories.VariantRepository
+	categoryRepo  *repositories.CategoryRepository
+	attributeRepo *repositories.AttributeRepository
+}
+
+func NewProductImportService(repo *repositories.ProductRepository, variantRepo *repositories.VariantRepository, categoryRepo *repositories.CategoryRepository, attributeRepo *repositories.AttributeRepository) *ProductImportService {
+	return &ProductImportService{repo: repo, variantRepo: variantRepo, categoryRepo: categoryRepo, attributeRepo: attributeRepo}
+}
+
+// Import - validasi semua baris lalu upsert berdasarkan SKU (SKU kosong = selalu produk baru).
+// mapping: kolom -> judul kolom di file, kolom yang tidak di-mapping dicari dengan nama kolomnya sendiri.
+// Dry run atau ada baris tidak valid = tidak ada yang disimpan
+func (s *ProductImportService) Import(data []byte, format string, mapping map[string]string, dryRun bool, user string) (*models.ProductImportReport, error) {
+	table, err := spreadsheet.Read(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(table) < 2 {
+		return nil, errors.New("file has no data rows")
+	}
+	definitions, err := s.attributeRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := importColumns(table[0], mapping, importFields(definitions))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.validate(table, columns, definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProductImportReport{DryRun: dryRun, TotalRows: len(rows), Rows: rows}
+	valid := make([]models.ProductImportRow, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			report.Invalid++
+			continue
+		}
+		report.Valid++
+		valid = append(valid, row)
+	}
+	if dryRun || report.Invalid > 0 || len(valid) == 0 {
+		return report, nil
+	}
+
+	if err := s.repo.Import(valid, user); err != nil {
+		return nil, err
+	}
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		row.ProductID = row.Product.ID
+		if row.Action == "create" {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// Export - seluruh katalog dengan kolom yang sama seperti import, status: active (default), archived, all
+func (s *ProductImportService) Export(w io.Writer, format string, status string) error {
+	if err := validateListStatus(status); err != nil {
+		return err
+	}
+
+	// kategori ditulis sebagai path supaya nama kembar di cabang berbeda tetap bisa di-import ulang
+	categories, err := s.categoryRepo.GetTree(models.ListAll)
+	if err != nil {
+		return err
+	}
+	paths := make(map[int]string)
+	for _, c := range categories {
+		paths[c.ID] = c.Path
+	}
+	definitions, err := s.attributeRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	table := [][]string{importFields(definitions)}
+	filter := models.ProductFilter{Status: status, Page: 1, PageSize: 500}
+	for {
+		products, _, err := s.repo.GetAll(filter)
+		if err != nil {
+			return err
+		}
+		for _, p := range products {
+			record := []string{
+				p.SKU, p.Name, paths[p.CategoryID], strconv.Itoa(p.Price), strconv.Itoa(p.CostPrice), strconv.Itoa(p.Stock),
+				p.Barcode, p.BaseUnit, strconv.Itoa(p.MinStock), strconv.Itoa(p.ReorderQty), strings.Join(p.Tags, ", "),
+			}
+			for _, d := range definitions {
+				record = append(record, formatAttributeValue(p.Attributes[d.Key]))
+			}
+			table = append(table, record)
+		}
+		if len(products) < filter.PageSize {
+			break
+		}
+		filter.Page++
+	}
+
+	switch format {
+	case spreadsheet.FormatCSV:
+		return spreadsheet.WriteCSV(w, table)
+	case spreadsheet.FormatXLSX:
+		return spreadsheet.WriteXLSX(w, table)
+	default:
+		return fmt.Errorf("unsupported format %q, use csv or xlsx", format)
+	}
+}
+
+// importFields - kolom standar ditambah satu kolom "attr:<key>" per atribut custom
+func importFields(definitions []models.AttributeDefinition) []string {
+	fields := append([]string(nil), models.ProductImportColumns...)
+	for _, d := range definitions {
+		fields = append(fields, models.ProductImportAttributePrefix+d.Key)
+	}
+	return fields
+}
+
+// importColumns - kolom -> index di file
+func importColumns(header []string, mapping map[string]string, fields []string) (map[string]int, error) {
+	known := make(map[string]bool)
+	for _, c := range fields {
+		known[c] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown column %q in mapping, use %s", field, strings.Join(fields, ", "))
+		}
+	}
+
+	positions := make(map[string]int)
+	for i, h := range header {
+		positions[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	columns := make(map[string]int)
+	for _, field := range fields {
+		title, mapped := mapping[field]
+		if !mapped {
+			title = field
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(title))]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s not found in file", title, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+
+	_, hasSKU := columns["sku"]
+	_, hasName := columns["name"]
+	if !hasSKU && !hasName {
+		return nil, errors.New("file must have a sku or name column")
+	}
+	return columns, nil
+}
+
+func (s *ProductImportService) validate(table [][]string, columns map[string]int, definitions []models.AttributeDefinition) ([]models.ProductImportRow, error) {
+	cell := func(record []string, field string) (string, bool) {
+		i, ok := columns[field]
+		if !ok {
+			return "", false
+		}
+		if i >= len(record) {
+			return "", true
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+
+	skus := make([]string, 0)
+	barcodes := make([]string, 0)
+	for _, record := range table[1:] {
+		if sku, _ := cell(record, "sku"); sku != "" {
+			skus = append(skus, sku)
+		}
+		if code, _ := cell(record, "barcode"); code != "" {
+			barcodes = append(barcodes, code)
+		}
+	}
+	existing, err := s.repo.GetBySKUs(skus)
+	if err != nil {
+		return nil, err
+	}
+	barcodeOwners, err := s.repo.GetBarcodeOwners(barcodes)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryRepo.GetNameIndex()
+	if err != nil {
+		return nil, err
+	}
+	attributeIndex := make(map[string]models.AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		attributeIndex[d.Key] = d
+	}
+
+	seenSKU := make(map[string]int)
+	seenBarcode := make(map[string]int)
+	rows := make([]models.ProductImportRow, 0, len(table)-1)
+	for i, record := range table[1:] {
+		row := models.ProductImportRow{Row: i + 2, Errors: []string{}, Warnings: []string{}}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		row.SKU, _ = cell(record, "sku")
+		product := &models.Product{}
+		if p, ok := existing[row.SKU]; ok && row.SKU != "" {
+			product = &p
+			row.Action = "update"
+		} else {
+			product.SKU = row.SKU
+			row.Action = "create"
+		}
+		row.Product = product
+
+		if row.SKU != "" {
+			if first, dup := seenSKU[row.SKU]; dup {
+				row.Errors = append(row.Errors, fmt.Sprintf("duplicate sku %s, already on row %d", row.SKU, first))
+			}
+			seenSKU[row.SKU] = row.Row
+		}
+
+		if name, ok := cell(record, "name"); ok && name != "" {
+			product.Name = name
+		} else if row.Action == "create" {
+			row.Errors = append(row.Errors, "name is required for new products")
+		}
+		row.Name = product.Name
+
+		for _, f := range []struct {
+			column string
+			target *int
+		}{
+			{"price", &product.Price},
+			{"cost_price", &product.CostPrice},
+			{"stock", &product.Stock},
+			{"min_stock", &product.MinStock},
+			{"reorder_qty", &product.ReorderQty},
+		} {
+			value, ok := cell(record, f.column)
+			if !ok || value == "" {
+				continue
+			}
+			n, err := parseImportInt(value)
+			if err != nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("%s must be a whole number", f.column))
+				continue
+			}
+			if n < 0 && f.column != "stock" {
+				row.Errors = append(row.Errors, fmt.Sprintf("%s cannot be negative", f.column))
+				continue
+			}
+
+			// stok dan harga modal bundle turunan dari komponen, nilai hasil export diabaikan
+			if row.Action == "update" && product.IsBundle && (f.column == "stock" || f.column == "cost_price") {
+				continue
+			}
+			if row.Action == "update" && f.column == "stock" && n != product.Stock {
+				variants, err := s.variantRepo.GetByProduct(product.ID)
+				if err != nil {
+					return nil, err
+				}
+				if len(variants) > 0 {
+					row.Warnings = append(row.Warnings, "stock of a product with variants is the sum of its variants, value is ignored")
+					continue
+				}
+			}
+			*f.target = n
+		}
+
+		if code, ok := cell(record, "barcode"); ok {
+			if err := validateBarcode(code); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			} else if code != "" {
+				if first, dup := seenBarcode[code]; dup {
+					row.Errors = append(row.Errors, fmt.Sprintf("duplicate barcode %s, already on row %d", code, first))
+				} else if owner, used := barcodeOwners[code]; used && (row.Action == "create" || owner != product.ID) {
+					row.Errors = append(row.Errors, fmt.Sprintf("barcode %s is already used by another product", code))
+				}
+				seenBarcode[code] = row.Row
+			}
+			product.Barcode = code
+		}
+
+		if name, ok := cell(record, "category"); ok {
+			product.CategoryID = 0
+			if name != "" {
+				id, found := categories[strings.ToLower(categoryPath(name))]
+				if !found {
+					row.Errors = append(row.Errors, fmt.Sprintf("unknown category %q", name))
+				}
+				product.CategoryID = id
+			}
+		}
+
+		if unit, ok := cell(record, "base_unit"); ok {
+			product.BaseUnit = unit
+		}
+
+		if value, ok := cell(record, "tags"); ok {
+			tags, err := normalizeTags(strings.Split(value, ","))
+			if err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+			product.Tags = tags
+		}
+
+		// kolom atribut yang tidak ada di file tidak mengubah nilai lama, sel kosong = hapus nilai
+		if product.Attributes == nil {
+			product.Attributes = map[string]interface{}{}
+		}
+		attributeColumns := false
+		for _, d := range definitions {
+			value, ok := cell(record, models.ProductImportAttributePrefix+d.Key)
+			if !ok {
+				continue
+			}
+			attributeColumns = true
+			if value == "" {
+				delete(product.Attributes, d.Key)
+				continue
+			}
+			parsed, err := parseAttributeValue(d, value)
+			if err != nil {
+				row.Errors = append(row.Errors, err.Error())
+				continue
+			}
+			product.Attributes[d.Key] = parsed
+		}
+		if err := validateAttributes(attributeIndex, product.Attributes, row.Action == "create" || attributeColumns); err != nil {
+			row.Errors = append(row.Errors, err.Error())
+		}
+		setBaseUnit(product)
+		if err := validateLotPolicy(product); err != nil {
+			row.Errors = append(row.Errors, err.Error())
+		}
+
+		if len(row.Errors) > 0 {
+			row.Action = ""
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseImportInt - angka bulat, desimal .0 dari Excel diterima
+func parseImportInt(value string) (int, error) {
+	if whole, frac, ok := strings.Cut(value, "."); ok && strings.Trim(frac, "0") == "" {
+		value = whole
+	}
+	return strconv.Atoi(value)
+}
+
+// categoryPath - rapikan spasi di sekitar ">" supaya "Minuman>Kopi" cocok dengan "Minuman > Kopi"
+func categoryPath(name string) string {
+	parts := strings.Split(name, ">")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return strings.Join(parts, " > ")
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
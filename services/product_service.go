@@ -1,34 +1,437 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"kasir-api/barcode"
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"kasir-api/storage"
+	"sort"
+	"strings"
 )
 
 type ProductService struct {
-	repo *repositories.ProductRepository
+	repo          *repositories.ProductRepository
+	variantRepo   *repositories.VariantRepository
+	bundleRepo    *repositories.BundleRepository
+	unitRepo      *repositories.UnitRepository
+	categoryRepo  *repositories.CategoryRepository
+	attributeRepo *repositories.AttributeRepository
+	storage       storage.Storage
 }
 
-func NewProductService(repo *repositories.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo *repositories.ProductRepository, variantRepo *repositories.VariantRepository, bundleRepo *repositories.BundleRepository, unitRepo *repositories.UnitRepository, categoryRepo *repositories.CategoryRepository, attributeRepo *repositories.AttributeRepository, storage storage.Storage) *ProductService {
+	return &ProductService{repo: repo, variantRepo: variantRepo, bundleRepo: bundleRepo, unitRepo: unitRepo, categoryRepo: categoryRepo, attributeRepo: attributeRepo, storage: storage}
 }
 
-func (s *ProductService) GetAll(name string) ([]models.Product, error) {
-	return s.repo.GetAll(name)
+func (s *ProductService) GetAll(filter models.ProductFilter) (*models.ProductPage, error) {
+	var err error
+	filter.Page, filter.PageSize, err = normalizePage(filter.Page, filter.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateListStatus(filter.Status); err != nil {
+		return nil, err
+	}
+	switch filter.Sort {
+	case "", "name", "price", "stock", "updated_at":
+	default:
+		return nil, errors.New("Invalid sort, use name, price, stock or updated_at")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, errors.New("min_price cannot be greater than max_price")
+	}
+	if filter.Tags, err = normalizeTags(filter.Tags); err != nil {
+		return nil, err
+	}
+	if filter.NumericAttributes, err = s.numericAttributeFilter(filter.Attributes); err != nil {
+		return nil, err
+	}
+
+	products, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		productImageURLs(s.storage, &products[i])
+	}
+	return &models.ProductPage{Products: products, Pagination: newPagination(filter.Page, filter.PageSize, total)}, nil
+}
+
+// GetByCategory - isi satu kategori, default hanya produk yang langsung di kategori tersebut
+func (s *ProductService) GetByCategory(categoryID int, filter models.ProductFilter) (*models.ProductPage, error) {
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return nil, err
+	}
+	filter.CategoryID = categoryID
+	return s.GetAll(filter)
 }
 
-func (s *ProductService) Create(data *models.Product) error {
-	return s.repo.Create(data)
+// Search - pencarian kasir (search-as-you-type), limit default 20 maksimal 50.
+// tags dan attributes opsional untuk mempersempit hasil
+func (s *ProductService) Search(q string, limit int, tags []string, attributes map[string]string) ([]models.ProductSearchResult, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, errors.New("q is required")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	numeric, err := s.numericAttributeFilter(attributes)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.repo.Search(q, min(limit, 50), tags, attributes, numeric)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].ImageThumbURL != "" {
+			results[i].ImageThumbURL = s.storage.URL(results[i].ImageThumbURL)
+		}
+	}
+	return results, nil
+}
+
+func (s *ProductService) Create(data *models.Product, user string) error {
+	if err := s.validateProduct(data, true); err != nil {
+		return err
+	}
+	return s.repo.Create(data, user)
 }
 
 func (s *ProductService) GetByID(id int) (*models.Product, error) {
-	return s.repo.GetByID(id)
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	productImageURLs(s.storage, product)
+
+	if product.CategoryID > 0 {
+		product.Category, err = s.categoryRepo.GetByID(product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		product.CategoryName = product.Category.Name
+	}
+	product.Options, err = s.variantRepo.GetOptions(id)
+	if err != nil {
+		return nil, err
+	}
+	product.Variants, err = s.variantRepo.GetByProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	product.Units, err = s.unitRepo.GetByProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsBundle {
+		product.Components, err = s.bundleRepo.GetComponents(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return product, nil
+}
+
+func (s *ProductService) Update(product *models.Product, user string) error {
+	if err := s.validateProduct(product, false); err != nil {
+		return err
+	}
+
+	// stok produk bervarian selalu total stok variannya
+	variants, err := s.variantRepo.GetByProduct(product.ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		product.Stock = 0
+		for _, v := range variants {
+			product.Stock += v.Stock
+		}
+	}
+
+	return s.repo.Update(product, user)
+}
+
+// productPatchFields - field yang bisa diubah lewat PATCH
+var productPatchFields = []string{"name", "price", "cost_price", "stock", "barcode", "sku", "category_id", "lot_policy", "allow_expired_sale", "min_stock", "reorder_qty", "base_unit", "tags", "attributes"}
+
+// Patch - JSON Merge Patch, hanya field yang dikirim yang disimpan.
+// Stok tidak ikut tertulis kecuali "stock" ada di patch, jadi tidak bentrok dengan pengurangan stok dari checkout.
+// "attributes" digabung per key (null = hapus key), "tags" selalu diganti seluruhnya
+func (s *ProductService) Patch(id int, data []byte, version int, user string) (*models.Product, error) {
+	patch, err := decodeMergePatch(data, productPatchFields)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 && version != product.Version {
+		return nil, models.ErrVersionConflict
+	}
+
+	_, patchStock := patch["stock"]
+	_, patchCost := patch["cost_price"]
+	if product.IsBundle && (patchStock || patchCost) {
+		return nil, errors.New("stock and cost_price of a bundle come from its components")
+	}
+	if patchStock {
+		variants, err := s.variantRepo.GetByProduct(id)
+		if err != nil {
+			return nil, err
+		}
+		if len(variants) > 0 {
+			return nil, errors.New("stock of a product with variants is the sum of its variants, update the variants instead")
+		}
+	}
+
+	err = applyMergePatch(patch, map[string]interface{}{
+		"name":               &product.Name,
+		"price":              &product.Price,
+		"cost_price":         &product.CostPrice,
+		"stock":              &product.Stock,
+		"barcode":            &product.Barcode,
+		"sku":                &product.SKU,
+		"category_id":        &product.CategoryID,
+		"lot_policy":         &product.LotPolicy,
+		"allow_expired_sale": &product.AllowExpiredSale,
+		"min_stock":          &product.MinStock,
+		"reorder_qty":        &product.ReorderQty,
+		"base_unit":          &product.BaseUnit,
+		"tags":               &product.Tags,
+		"attributes":         &product.Attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(product.Name) == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+	_, patchAttributes := patch["attributes"]
+	if err := s.validateProduct(product, patchAttributes); err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(patch))
+	for field := range patch {
+		columns = append(columns, field)
+	}
+	sort.Strings(columns)
+
+	product.Version = version
+	if err := s.repo.Patch(product, columns, user); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// Archive - pengganti hard delete, histori transaksi tetap utuh
+func (s *ProductService) Archive(id int, version int) error {
+	return s.repo.Archive(id, version)
+}
+
+func (s *ProductService) Restore(id int, version int) (*models.Product, error) {
+	if err := s.repo.Restore(id, version); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// Purge - hapus permanen produk arsip tanpa histori beserta file gambarnya
+func (s *ProductService) Purge(id int, version int) error {
+	keys, err := s.repo.Purge(id, version)
+	if err != nil {
+		return err
+	}
+	deleteImageFiles(s.storage, keys)
+	return nil
+}
+
+// AssignBarcode - buat EAN-13 internal untuk produk yang belum punya barcode
+func (s *ProductService) AssignBarcode(id int) (*models.Product, error) {
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Barcode != "" {
+		return nil, errors.New("Product already has a barcode")
+	}
+
+	code, err := barcode.InternalEAN13(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetBarcode(product.ID, code); err != nil {
+		return nil, err
+	}
+
+	product.Barcode = code
+	return product, nil
+}
+
+// AssignMissingBarcodes - buat EAN-13 internal untuk semua produk tanpa barcode
+func (s *ProductService) AssignMissingBarcodes() ([]models.Product, error) {
+	products, err := s.repo.GetWithoutBarcode()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range products {
+		code, err := barcode.InternalEAN13(products[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.SetBarcode(products[i].ID, code); err != nil {
+			return nil, err
+		}
+		products[i].Barcode = code
+	}
+
+	return products, nil
+}
+
+// Barcode - encode barcode produk, kind kosong = otomatis (EAN-13 kalau valid, selain itu Code128)
+func (s *ProductService) Barcode(id int, kind string) (*barcode.Barcode, error) {
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return productBarcode(product, kind)
+}
+
+func productBarcode(product *models.Product, kind string) (*barcode.Barcode, error) {
+	if product.Barcode == "" {
+		return nil, errors.New("Product has no barcode")
+	}
+	if kind == "" {
+		return barcode.Auto(product.Barcode)
+	}
+	return barcode.Encode(kind, product.Barcode)
+}
+
+// validateBarcode - kode 13 digit angka dianggap EAN-13 dan check digit-nya harus benar
+func validateBarcode(code string) error {
+	if len(code) != 13 {
+		return nil
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return nil
+		}
+	}
+	if !barcode.ValidEAN13(code) {
+		return errors.New("Invalid EAN-13 check digit")
+	}
+	return nil
+}
+
+// validateLotPolicy - kosong = fifo
+func validateLotPolicy(product *models.Product) error {
+	switch product.LotPolicy {
+	case "":
+		product.LotPolicy = models.LotPolicyFIFO
+	case models.LotPolicyFIFO, models.LotPolicyFEFO:
+	default:
+		return errors.New("Invalid lot_policy, use fifo or fefo")
+	}
+	return nil
+}
+
+// setBaseUnit - satuan dasar default "pcs"
+func setBaseUnit(product *models.Product) {
+	product.BaseUnit = strings.TrimSpace(product.BaseUnit)
+	if product.BaseUnit == "" {
+		product.BaseUnit = "pcs"
+	}
+}
+
+// numericAttributeFilter - key filter atribut bertipe number, nilainya harus angka.
+// Key yang tidak terdefinisi tetap dibandingkan sebagai teks
+func (s *ProductService) numericAttributeFilter(attributes map[string]string) (map[string]bool, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	definitions, err := s.attributeRepo.GetIndex()
+	if err != nil {
+		return nil, err
+	}
+	numeric := map[string]bool{}
+	for key, value := range attributes {
+		d, ok := definitions[key]
+		if !ok || d.Type != models.AttributeNumber {
+			continue
+		}
+		if _, err := parseAttributeValue(d, strings.TrimSpace(value)); err != nil {
+			return nil, err
+		}
+		numeric[key] = true
+	}
+	return numeric, nil
 }
 
-func (s *ProductService) Update(product *models.Product) error {
-	return s.repo.Update(product)
+// validateProduct - validasi dan default yang sama untuk create, update dan patch,
+// requireAttributes = atribut required wajib terisi
+func (s *ProductService) validateProduct(product *models.Product, requireAttributes bool) error {
+	if err := validateBarcode(product.Barcode); err != nil {
+		return err
+	}
+	if product.CostPrice < 0 {
+		return errors.New("cost_price cannot be negative")
+	}
+	if err := validateLotPolicy(product); err != nil {
+		return err
+	}
+	if product.MinStock < 0 || product.ReorderQty < 0 {
+		return errors.New("min_stock and reorder_qty cannot be negative")
+	}
+	if err := s.validateCategory(product.CategoryID); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(product.Tags)
+	if err != nil {
+		return err
+	}
+	product.Tags = tags
+	definitions, err := s.attributeRepo.GetIndex()
+	if err != nil {
+		return err
+	}
+	if product.Attributes == nil {
+		product.Attributes = map[string]interface{}{}
+	}
+	if err := validateAttributes(definitions, product.Attributes, requireAttributes); err != nil {
+		return err
+	}
+	setBaseUnit(product)
+	product.SKU = strings.TrimSpace(product.SKU)
+	return nil
 }
 
-func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+// validateCategory - category_id 0 = produk tanpa kategori, kategori arsip tidak bisa dipakai
+func (s *ProductService) validateCategory(categoryID int) error {
+	if categoryID < 0 {
+		return errors.New("Invalid category_id")
+	}
+	if categoryID == 0 {
+		return nil
+	}
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return fmt.Errorf("category id %d not found", categoryID)
+	}
+	if category.ArchivedAt != nil {
+		return fmt.Errorf("category id %d is archived", categoryID)
+	}
+	return nil
 }
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"time"
+)
+
+type PurchaseOrderService struct {
+	repo         *repositories.PurchaseOrderRepository
+	supplierRepo *repositories.SupplierRepository
+}
+
+func NewPurchaseOrderService(repo *repositories.PurchaseOrderRepository, supplierRepo *repositories.SupplierRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo, supplierRepo: supplierRepo}
+}
+
+func (s *PurchaseOrderService) GetAll(status string, supplierID int) ([]models.PurchaseOrder, error) {
+	return s.repo.GetAll(status, supplierID)
+}
+
+func (s *PurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Create(req models.PurchaseOrderRequest, user string) (*models.PurchaseOrder, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Create(req, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Update(id int, req models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(id, req); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) MarkOrdered(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.MarkOrdered(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Receive - penerimaan barang bisa bertahap sampai semua item PO terpenuhi
+func (s *PurchaseOrderService) Receive(id int, req models.GoodsReceiptRequest, user string) (*models.PurchaseOrder, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("Items are required")
+	}
+	for _, item := range req.Items {
+		if item.ExpiryDate != "" {
+			if _, err := time.Parse("2006-01-02", item.ExpiryDate); err != nil {
+				return nil, errors.New("Invalid expiry_date, use YYYY-MM-DD")
+			}
+		}
+	}
+
+	if _, err := s.repo.Receive(id, req, user); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) GetOutstanding(supplierID int) ([]models.OutstandingPO, error) {
+	return s.repo.GetOutstanding(supplierID)
+}
+
+func (s *PurchaseOrderService) validateRequest(req models.PurchaseOrderRequest) error {
+	if _, err := s.supplierRepo.GetByID(req.SupplierID); err != nil {
+		return err
+	}
+	if len(req.Items) == 0 {
+		return errors.New("Items are required")
+	}
+	if req.ExpectedAt != "" {
+		if _, err := time.Parse("2006-01-02", req.ExpectedAt); err != nil {
+			return errors.New("Invalid expected_at, use YYYY-MM-DD")
+		}
+	}
+	return nil
+}
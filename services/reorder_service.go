@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"math"
+)
+
+type ReorderService struct {
+	repo                 *repositories.ReorderRepository
+	purchaseOrderService *PurchaseOrderService
+}
+
+func NewReorderService(repo *repositories.ReorderRepository, purchaseOrderService *PurchaseOrderService) *ReorderService {
+	return &ReorderService{repo: repo, purchaseOrderService: purchaseOrderService}
+}
+
+// Suggestions - rata-rata jual harian x (lead time + coverage) + min_stock, dikurangi stok dan yang sedang dipesan.
+// Dibulatkan ke atas ke kelipatan reorder_qty kalau diisi
+func (s *ReorderService) Suggestions(params models.ReorderParams) ([]models.ReorderSuggestion, error) {
+	if params.WindowDays == 0 {
+		params.WindowDays = 30
+	}
+	if params.CoverageDays == 0 {
+		params.CoverageDays = 7
+	}
+	if params.WindowDays < 0 || params.CoverageDays < 0 {
+		return nil, errors.New("window_days and coverage_days cannot be negative")
+	}
+
+	items, err := s.repo.GetStockVelocity(params.WindowDays, params.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.ReorderSuggestion, 0)
+	for _, item := range items {
+		avg := float64(item.QtySold) / float64(params.WindowDays)
+		item.AvgDailySales = math.Round(avg*100) / 100
+		if avg > 0 {
+			days := math.Round(float64(item.Stock)/avg*10) / 10
+			item.DaysOfStock = &days
+		}
+
+		item.ReorderPoint = int(math.Ceil(avg*float64(item.LeadTimeDays))) + item.MinStock
+		target := int(math.Ceil(avg*float64(item.LeadTimeDays+params.CoverageDays))) + item.MinStock
+		need := target - item.Stock - item.OnOrder
+		if need > 0 {
+			item.SuggestedQty = need
+			if item.ReorderQty > 0 {
+				item.SuggestedQty = (need + item.ReorderQty - 1) / item.ReorderQty * item.ReorderQty
+			}
+		}
+		item.EstimatedCost = item.SuggestedQty * item.UnitCost
+
+		if item.SuggestedQty > 0 || params.IncludeAll {
+			suggestions = append(suggestions, item)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// CreatePurchaseOrder - jadikan saran (atau item yang sudah diedit manajer) draft PO ke satu supplier
+func (s *ReorderService) CreatePurchaseOrder(req models.ReorderPORequest, user string) (*models.PurchaseOrder, error) {
+	if req.SupplierID == 0 {
+		return nil, errors.New("supplier_id is required")
+	}
+
+	items := req.Items
+	if len(items) == 0 {
+		req.IncludeAll = false
+		suggestions, err := s.Suggestions(req.ReorderParams)
+		if err != nil {
+			return nil, err
+		}
+		for _, sg := range suggestions {
+			items = append(items, models.PurchaseOrderItemRequest{
+				ProductID: sg.ProductID,
+				VariantID: sg.VariantID,
+				Quantity:  sg.SuggestedQty,
+				UnitCost:  sg.UnitCost,
+			})
+		}
+		if len(items) == 0 {
+			return nil, errors.New("Nothing to reorder for this supplier")
+		}
+	}
+
+	note := req.Note
+	if note == "" {
+		note = "generated from reorder suggestions"
+	}
+	return s.purchaseOrderService.Create(models.PurchaseOrderRequest{
+		SupplierID: req.SupplierID,
+		Note:       note,
+		ExpectedAt: req.ExpectedAt,
+		Items:      items,
+	}, user)
+}
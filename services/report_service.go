@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"kasir-api/models"
 	"kasir-api/repositories"
 )
@@ -20,3 +21,26 @@ func (s *ReportService) GetReport() (*models.Today, error) {
 func (s *ReportService) GetReportDate(start_date string, end_date string) ([]models.ReportData, error) {
 	return s.repo.GetReportDate(start_date, end_date)
 }
+
+func (s *ReportService) GetProductSales(start_date string, end_date string, rollup string) ([]models.ProductSales, error) {
+	return s.repo.GetProductSales(start_date, end_date, rollup)
+}
+
+// GetCategorySales - parent_id dan level tidak bisa dipakai bersamaan
+func (s *ReportService) GetCategorySales(start_date string, end_date string, parentID int, level int) ([]models.CategorySales, error) {
+	if parentID < 0 || level < 0 {
+		return nil, errors.New("parent_id and level cannot be negative")
+	}
+	if parentID > 0 && level > 0 {
+		return nil, errors.New("use either parent_id or level, not both")
+	}
+	return s.repo.GetCategorySales(start_date, end_date, parentID, level)
+}
+
+func (s *ReportService) GetModifierSales(start_date string, end_date string) ([]models.ModifierSales, error) {
+	return s.repo.GetModifierSales(start_date, end_date)
+}
+
+func (s *ReportService) GetTransactionProfits(start_date string, end_date string) ([]models.Transaction, error) {
+	return s.repo.GetTransactionProfits(start_date, end_date)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"kasir-api/alerts"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"log"
+)
+
+type StockAlertService struct {
+	repo     *repositories.StockAlertRepository
+	notifier alerts.Notifier
+}
+
+func NewStockAlertService(repo *repositories.StockAlertRepository, notifier alerts.Notifier) *StockAlertService {
+	return &StockAlertService{repo: repo, notifier: notifier}
+}
+
+// GetLowStock - belum ada konsep outlet, jadi daftar ini untuk seluruh stok toko
+func (s *StockAlertService) GetLowStock() ([]models.LowStockItem, error) {
+	return s.repo.GetLowStock()
+}
+
+func (s *StockAlertService) GetAll(limit int) ([]models.StockAlert, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.repo.GetAll(limit)
+}
+
+// CheckTransaction - dipanggil setelah checkout commit, kegagalan alert tidak menggagalkan checkout
+func (s *StockAlertService) CheckTransaction(transactionID int) {
+	created, err := s.repo.CreateForTransaction(transactionID)
+	if err != nil {
+		log.Printf("stock alert for transaction %d: %v", transactionID, err)
+		return
+	}
+	if len(created) == 0 {
+		return
+	}
+
+	// webhook bisa lambat, jangan tahan response checkout
+	go func() {
+		for _, alert := range created {
+			if err := s.notifier.Notify(alert); err != nil {
+				log.Printf("stock alert notify for product %d: %v", alert.ProductID, err)
+			}
+		}
+	}()
+}
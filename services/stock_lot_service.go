@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"time"
+)
+
+type StockLotService struct {
+	repo        *repositories.StockLotRepository
+	productRepo *repositories.ProductRepository
+}
+
+func NewStockLotService(repo *repositories.StockLotRepository, productRepo *repositories.ProductRepository) *StockLotService {
+	return &StockLotService{repo: repo, productRepo: productRepo}
+}
+
+func (s *StockLotService) GetByProduct(productID int, includeEmpty bool) ([]models.StockLot, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProduct(productID, includeEmpty)
+}
+
+// Create - lot baru dari stok yang belum punya lot, stok dari supplier masuk lewat penerimaan PO
+func (s *StockLotService) Create(productID int, req models.StockLotRequest) (*models.StockLot, error) {
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+	if req.UnitCost < 0 {
+		return nil, errors.New("unit_cost cannot be negative")
+	}
+	if req.ExpiryDate != "" {
+		if _, err := time.Parse("2006-01-02", req.ExpiryDate); err != nil {
+			return nil, errors.New("Invalid expiry_date, use YYYY-MM-DD")
+		}
+	}
+
+	id, err := s.repo.Create(productID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *StockLotService) GetExpiring(days int) ([]models.StockLot, error) {
+	if days < 0 {
+		return nil, errors.New("days cannot be negative")
+	}
+	return s.repo.GetExpiring(days)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type StockMovementService struct {
+	repo        *repositories.StockMovementRepository
+	productRepo *repositories.ProductRepository
+	variantRepo *repositories.VariantRepository
+}
+
+func NewStockMovementService(repo *repositories.StockMovementRepository, productRepo *repositories.ProductRepository, variantRepo *repositories.VariantRepository) *StockMovementService {
+	return &StockMovementService{repo: repo, productRepo: productRepo, variantRepo: variantRepo}
+}
+
+func (s *StockMovementService) GetByProduct(productID int, start_date string, end_date string, limit int) ([]models.StockMovement, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProduct(productID, start_date, end_date, limit)
+}
+
+// Adjust - koreksi manual, reason yang boleh: adjustment, refund, transfer
+func (s *StockMovementService) Adjust(productID int, req models.StockAdjustmentRequest, user string) (*models.StockMovement, error) {
+	if req.QuantityDelta == 0 {
+		return nil, errors.New("quantity_delta cannot be 0")
+	}
+	if req.Reason == "" {
+		req.Reason = models.MovementAdjustment
+	}
+	switch req.Reason {
+	case models.MovementAdjustment, models.MovementRefund, models.MovementTransfer:
+	default:
+		return nil, errors.New("Invalid reason, use adjustment, refund or transfer")
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsBundle {
+		return nil, errors.New("Bundle stock follows its components and cannot be adjusted")
+	}
+	if req.VariantID == 0 {
+		variants, err := s.variantRepo.GetByProduct(productID)
+		if err != nil {
+			return nil, err
+		}
+		if len(variants) > 0 {
+			return nil, errors.New("variant_id is required for products with variants")
+		}
+	}
+
+	movement := &models.StockMovement{
+		ProductID:     productID,
+		VariantID:     req.VariantID,
+		QuantityDelta: req.QuantityDelta,
+		Reason:        req.Reason,
+		ReferenceType: "manual",
+		User:          user,
+		Note:          req.Note,
+	}
+	if err := s.repo.Adjust(movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
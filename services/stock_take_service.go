@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type StockTakeService struct {
+	repo         *repositories.StockTakeRepository
+	categoryRepo *repositories.CategoryRepository
+}
+
+func NewStockTakeService(repo *repositories.StockTakeRepository, categoryRepo *repositories.CategoryRepository) *StockTakeService {
+	return &StockTakeService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *StockTakeService) GetAll() ([]models.StockTake, error) {
+	return s.repo.GetAll()
+}
+
+func (s *StockTakeService) GetByID(id int) (*models.StockTake, error) {
+	st, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	st.Summary = summarizeStockTake(st.Lines)
+	return st, nil
+}
+
+// Start - category_id 0 = semua produk. Belum ada konsep outlet, jadi sesi hanya bisa dibatasi per kategori
+func (s *StockTakeService) Start(req models.StartStockTakeRequest, user string) (*models.StockTake, error) {
+	if req.CategoryID > 0 {
+		if _, err := s.categoryRepo.GetByID(req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	st := &models.StockTake{CategoryID: req.CategoryID, Note: req.Note, CreatedBy: user}
+	if err := s.repo.Start(st); err != nil {
+		return nil, err
+	}
+	return s.GetByID(st.ID)
+}
+
+func (s *StockTakeService) SubmitCounts(id int, counts []models.StockTakeCount) (*models.StockTake, error) {
+	if len(counts) == 0 {
+		return nil, errors.New("Counts are required")
+	}
+	for _, c := range counts {
+		if c.Mode != "" && c.Mode != "set" && c.Mode != "add" {
+			return nil, errors.New("Invalid mode, use set or add")
+		}
+	}
+
+	if err := s.repo.SubmitCounts(id, counts); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// Variances - hanya baris yang selisih atau belum dihitung, ringkasan tetap dari semua baris
+func (s *StockTakeService) Variances(id int) (*models.StockTake, error) {
+	st, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	st.Lines = varianceLines(st.Lines)
+	return st, nil
+}
+
+// Approve - posting selisih ke stok, hasilnya laporan nilai selisih
+func (s *StockTakeService) Approve(id int, req models.StockTakeApproveRequest, user string) (*models.StockTake, error) {
+	if err := s.repo.Approve(id, req.ZeroUncounted, user); err != nil {
+		return nil, err
+	}
+	return s.Variances(id)
+}
+
+func (s *StockTakeService) Cancel(id int) (*models.StockTake, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+func varianceLines(lines []models.StockTakeLine) []models.StockTakeLine {
+	result := make([]models.StockTakeLine, 0)
+	for _, l := range lines {
+		if l.Counted == nil || l.Variance != 0 {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+func summarizeStockTake(lines []models.StockTakeLine) *models.StockTakeSummary {
+	summary := &models.StockTakeSummary{Lines: len(lines)}
+	for _, l := range lines {
+		if l.Counted == nil {
+			summary.Uncounted++
+			continue
+		}
+		summary.Counted++
+		if l.Variance < 0 {
+			summary.ShortageQty -= l.Variance
+			summary.ShortageValue -= l.VarianceValue
+		} else {
+			summary.SurplusQty += l.Variance
+			summary.SurplusValue += l.VarianceValue
+		}
+	}
+	summary.NetValue = summary.SurplusValue - summary.ShortageValue
+	return summary
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+)
+
+type TabService struct {
+	repo       *repositories.TabRepository
+	tableRepo  *repositories.TableRepository
+	kitchen    *KitchenService
+	stockAlert *StockAlertService
+}
+
+func NewTabService(repo *repositories.TabRepository, tableRepo *repositories.TableRepository, kitchen *KitchenService, stockAlert *StockAlertService) *TabService {
+	return &TabService{repo: repo, tableRepo: tableRepo, kitchen: kitchen, stockAlert: stockAlert}
+}
+
+func (s *TabService) GetOpen() ([]models.Tab, error) {
+	return s.repo.GetOpen()
+}
+
+func (s *TabService) GetByID(id int) (*models.Tab, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *TabService) Open(tableID int) (*models.Tab, error) {
+	if _, err := s.tableRepo.GetByID(tableID); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Open(tableID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *TabService) AddItems(tabID int, items []models.CheckoutItem) (*models.Tab, error) {
+	if len(items) == 0 {
+		return nil, errors.New("Items are required")
+	}
+	roundID, err := s.repo.AddItems(tabID, items)
+	if err != nil {
+		return nil, err
+	}
+	if roundID > 0 {
+		s.kitchen.Notify(models.KitchenEvent{TabRoundID: roundID, OrderStatus: models.OrderStatusNew})
+	}
+	return s.repo.GetByID(tabID)
+}
+
+func (s *TabService) RemoveItem(tabID, itemID int) (*models.Tab, error) {
+	if err := s.repo.RemoveItem(tabID, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(tabID)
+}
+
+func (s *TabService) Transfer(tabID, tableID int) (*models.Tab, error) {
+	if _, err := s.tableRepo.GetByID(tableID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Transfer(tabID, tableID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(tabID)
+}
+
+func (s *TabService) Merge(targetID, sourceID int) (*models.Tab, error) {
+	if err := s.repo.Merge(targetID, sourceID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(targetID)
+}
+
+// Settle - bayar sebagian item (split per item) atau semua, shares > 1 membagi total secara rata
+// dan mencatat pembayaran per bagian
+func (s *TabService) Settle(tabID int, req models.TabSettleRequest) (*models.TabSettlement, error) {
+	if req.Shares < 0 {
+		return nil, errors.New("Shares cannot be negative")
+	}
+
+	settlement, err := s.repo.Settle(tabID, req.ItemIDs, req.Shares)
+	if err != nil {
+		return nil, err
+	}
+	s.stockAlert.CheckTransaction(settlement.Transaction.ID)
+	return settlement, nil
+}
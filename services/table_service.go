@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type TableService struct {
+	repo *repositories.TableRepository
+}
+
+func NewTableService(repo *repositories.TableRepository) *TableService {
+	return &TableService{repo: repo}
+}
+
+func (s *TableService) GetAll(area string) ([]models.DiningTable, error) {
+	return s.repo.GetAll(area)
+}
+
+func (s *TableService) Create(table *models.DiningTable) error {
+	if strings.TrimSpace(table.Name) == "" {
+		return errors.New("Table name is required")
+	}
+	return s.repo.Create(table)
+}
+
+func (s *TableService) GetByID(id int) (*models.DiningTable, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *TableService) Update(table *models.DiningTable) error {
+	if strings.TrimSpace(table.Name) == "" {
+		return errors.New("Table name is required")
+	}
+	return s.repo.Update(table)
+}
+
+func (s *TableService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
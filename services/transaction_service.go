@@ -6,13 +6,25 @@ import (
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo       *repositories.TransactionRepository
+	kitchen    *KitchenService
+	stockAlert *StockAlertService
 }
 
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+func NewTransactionService(repo *repositories.TransactionRepository, kitchen *KitchenService, stockAlert *StockAlertService) *TransactionService {
+	return &TransactionService{repo: repo, kitchen: kitchen, stockAlert: stockAlert}
 }
 
 func (s *TransactionService) Checkout(items []models.CheckoutItem, useLock bool) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+	transaction, err := s.repo.CreateTransaction(items)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.OrderStatus != "" {
+		s.kitchen.Notify(models.KitchenEvent{TransactionID: transaction.ID, OrderStatus: transaction.OrderStatus})
+	}
+	s.stockAlert.CheckTransaction(transaction.ID)
+
+	return transaction, nil
 }
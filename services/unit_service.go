@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type UnitService struct {
+	repo        *repositories.UnitRepository
+	productRepo *repositories.ProductRepository
+}
+
+func NewUnitService(repo *repositories.UnitRepository, productRepo *repositories.ProductRepository) *UnitService {
+	return &UnitService{repo: repo, productRepo: productRepo}
+}
+
+func (s *UnitService) GetUnits(productID int) ([]models.ProductUnit, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProduct(productID)
+}
+
+func (s *UnitService) GetUnit(productID, id int) (*models.ProductUnit, error) {
+	return s.repo.GetByID(productID, id)
+}
+
+func (s *UnitService) CreateUnit(unit *models.ProductUnit) error {
+	if _, err := s.productRepo.GetByID(unit.ProductID); err != nil {
+		return err
+	}
+	if err := validateUnit(unit); err != nil {
+		return err
+	}
+	return s.repo.Create(unit)
+}
+
+func (s *UnitService) UpdateUnit(unit *models.ProductUnit) error {
+	if err := validateUnit(unit); err != nil {
+		return err
+	}
+	return s.repo.Update(unit)
+}
+
+func (s *UnitService) DeleteUnit(productID, id int) error {
+	return s.repo.Delete(productID, id)
+}
+
+// LookupBarcode - scan di kasir, bisa barcode produk atau barcode satuan (misal barcode slop)
+func (s *UnitService) LookupBarcode(code string) (*models.BarcodeMatch, error) {
+	if code == "" {
+		return nil, errors.New("barcode is required")
+	}
+	return s.repo.LookupBarcode(code)
+}
+
+func validateUnit(unit *models.ProductUnit) error {
+	unit.Name = strings.TrimSpace(unit.Name)
+	if unit.Name == "" {
+		return errors.New("Unit name is required")
+	}
+	if unit.Factor <= 0 {
+		return errors.New("factor must be greater than 0")
+	}
+	if unit.Price != nil && *unit.Price < 0 {
+		return errors.New("price cannot be negative")
+	}
+	return validateBarcode(unit.Barcode)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type VariantService struct {
+	repo        *repositories.VariantRepository
+	productRepo *repositories.ProductRepository
+}
+
+func NewVariantService(repo *repositories.VariantRepository, productRepo *repositories.ProductRepository) *VariantService {
+	return &VariantService{repo: repo, productRepo: productRepo}
+}
+
+func (s *VariantService) GetOptions(productID int) ([]models.ProductOption, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOptions(productID)
+}
+
+func (s *VariantService) SetOptions(productID int, options []models.ProductOption) error {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return err
+	}
+	if product.IsBundle && len(options) > 0 {
+		return errors.New("Bundle products cannot have variants")
+	}
+
+	names := map[string]bool{}
+	for _, o := range options {
+		if strings.TrimSpace(o.Name) == "" {
+			return errors.New("Option name is required")
+		}
+		if names[o.Name] {
+			return fmt.Errorf("Duplicate option %q", o.Name)
+		}
+		names[o.Name] = true
+
+		if len(o.Values) == 0 {
+			return fmt.Errorf("Option %q needs at least one value", o.Name)
+		}
+		values := map[string]bool{}
+		for _, v := range o.Values {
+			if strings.TrimSpace(v) == "" || values[v] {
+				return fmt.Errorf("Option %q has an empty or duplicate value", o.Name)
+			}
+			values[v] = true
+		}
+	}
+
+	return s.repo.SetOptions(productID, options)
+}
+
+func (s *VariantService) GetVariants(productID int) ([]models.ProductVariant, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProduct(productID)
+}
+
+func (s *VariantService) GetVariant(productID, id int) (*models.ProductVariant, error) {
+	variant, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != productID {
+		return nil, errors.New("Variant not found")
+	}
+	return variant, nil
+}
+
+// CreateVariant - tambah satu varian, opsi harus cocok dengan dimensi opsi produk
+func (s *VariantService) CreateVariant(variant *models.ProductVariant, user string) error {
+	options, err := s.GetOptions(variant.ProductID)
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		return errors.New("Product has no options, set options first")
+	}
+
+	values := make([]string, 0, len(options))
+	for _, o := range options {
+		value, ok := variant.Options[o.Name]
+		if !ok || !contains(o.Values, value) {
+			return fmt.Errorf("Invalid or missing value for option %q", o.Name)
+		}
+		values = append(values, value)
+	}
+	if len(variant.Options) != len(options) {
+		return errors.New("Variant has unknown options")
+	}
+
+	variant.Name = strings.Join(values, " / ")
+	if variant.SKU == "" {
+		variant.SKU = variantSKU(variant.ProductID, values)
+	}
+
+	return s.repo.Create(variant, user)
+}
+
+// GenerateVariants - buat varian untuk setiap kombinasi opsi yang belum ada, stok awal 0
+func (s *VariantService) GenerateVariants(productID int) ([]models.ProductVariant, error) {
+	options, err := s.GetOptions(productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, errors.New("Product has no options, set options first")
+	}
+
+	existing, err := s.repo.GetByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, v := range existing {
+		names[v.Name] = true
+	}
+
+	created := make([]models.ProductVariant, 0)
+	for _, combo := range combinations(options) {
+		values := make([]string, len(options))
+		for i, o := range options {
+			values[i] = combo[o.Name]
+		}
+		name := strings.Join(values, " / ")
+		if names[name] {
+			continue
+		}
+
+		variant := models.ProductVariant{
+			ProductID: productID,
+			SKU:       variantSKU(productID, values),
+			Name:      name,
+			Options:   combo,
+		}
+		if err := s.repo.Create(&variant, ""); err != nil {
+			return nil, err
+		}
+		created = append(created, variant)
+	}
+
+	return created, nil
+}
+
+// UpdateVariant - hanya SKU, harga override dan stok yang bisa diubah
+func (s *VariantService) UpdateVariant(variant *models.ProductVariant, user string) error {
+	current, err := s.GetVariant(variant.ProductID, variant.ID)
+	if err != nil {
+		return err
+	}
+	if variant.SKU == "" {
+		variant.SKU = current.SKU
+	}
+	if err := s.repo.Update(variant, user); err != nil {
+		return err
+	}
+
+	variant.Name = current.Name
+	variant.Options = current.Options
+	return nil
+}
+
+func (s *VariantService) DeleteVariant(productID, id int, user string) error {
+	return s.repo.Delete(productID, id, user)
+}
+
+// combinations - cartesian product semua nilai opsi
+func combinations(options []models.ProductOption) []map[string]string {
+	result := []map[string]string{{}}
+	for _, o := range options {
+		next := make([]map[string]string, 0, len(result)*len(o.Values))
+		for _, partial := range result {
+			for _, value := range o.Values {
+				combo := make(map[string]string, len(partial)+1)
+				for k, v := range partial {
+					combo[k] = v
+				}
+				combo[o.Name] = value
+				next = append(next, combo)
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// variantSKU - P12-M-MERAH
+func variantSKU(productID int, values []string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strings.ToUpper(strings.Join(strings.Fields(v), ""))
+	}
+	return fmt.Sprintf("P%d-%s", productID, strings.Join(parts, "-"))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
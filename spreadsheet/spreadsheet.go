@@ -0,0 +1,36 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Read - baca file jadi baris-baris sel, baris pertama biasanya header
+func Read(data []byte, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(data)
+	case FormatXLSX:
+		return ReadXLSX(data)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, use csv or xlsx", format)
+	}
+}
+
+// FormatFromName - tebak format dari ekstensi nama file, kosong kalau tidak dikenal
+func FormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	default:
+		return ""
+	}
+}
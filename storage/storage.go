@@ -0,0 +1,31 @@
+// Package storage menyimpan file upload (gambar produk) di belakang interface,
+// sekarang baru ada implementasi filesystem lokal.
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+type Storage interface {
+	// Save - simpan isi r dengan key berbentuk path relatif, misal "products/12/abc_thumb.jpg"
+	Save(key string, r io.Reader) error
+	// Delete - hapus file, key yang tidak ada bukan error
+	Delete(key string) error
+	// URL - URL publik untuk key
+	URL(key string) string
+}
+
+// checkKey - tolak key absolut atau yang keluar dari root storage
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.New("invalid storage key")
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return errors.New("invalid storage key")
+		}
+	}
+	return nil
+}